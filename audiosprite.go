@@ -0,0 +1,81 @@
+package howler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// AudioSprite is a sprite sheet generated by the audiosprite tool.
+type AudioSprite struct {
+	// Sources are the encoded sprite sheet files, in the order audiosprite wrote
+	// them.
+	Sources []string
+	// Sprites are the sprites in the sheet, keyed by name.
+	Sprites map[string]Sprite
+}
+
+// audioSpriteJSON is the default JSON output of audiosprite. Older versions
+// list the files under urls, newer ones under resources.
+type audioSpriteJSON struct {
+	URLs      []string `json:"urls"`
+	Resources []string `json:"resources"`
+	Spritemap map[string]struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Loop  bool    `json:"loop"`
+	} `json:"spritemap"`
+}
+
+// ParseAudioSprite decodes the JSON written by audiosprite, converting the
+// start and end of each sprite from seconds to a Sprite rounded to the
+// nearest millisecond.
+func ParseAudioSprite(r io.Reader) (*AudioSprite, error) {
+	var data audioSpriteJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("howler: parsing audiosprite: %w", err)
+	}
+
+	sources := data.URLs
+	if len(sources) == 0 {
+		sources = data.Resources
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("howler: parsing audiosprite: no urls or resources")
+	}
+
+	sprites := make(map[string]Sprite, len(data.Spritemap))
+	for name, entry := range data.Spritemap {
+		if entry.Start < 0 {
+			return nil, fmt.Errorf("howler: parsing audiosprite: sprite %q starts at negative time %gs", name, entry.Start)
+		}
+		if entry.End < entry.Start {
+			return nil, fmt.Errorf("howler: parsing audiosprite: sprite %q ends at %gs before it starts at %gs", name, entry.End, entry.Start)
+		}
+		start := seconds(entry.Start)
+		sprites[name] = Sprite{
+			Offset:   start,
+			Duration: seconds(entry.End) - start,
+			Loop:     entry.Loop,
+		}
+	}
+
+	return &AudioSprite{Sources: sources, Sprites: sprites}, nil
+}
+
+// Options returns the options for a Howl playing the sprite sheet.
+func (a *AudioSprite) Options() HowlOptions {
+	return HowlOptions{
+		Source:  a.Sources,
+		Sprites: a.Sprites,
+	}
+}
+
+// seconds converts seconds to a duration rounded to the nearest millisecond,
+// the precision howler.js uses for sprites.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s*1000)) * time.Millisecond
+}
@@ -0,0 +1,100 @@
+package howler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAudioSprite(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    *AudioSprite
+		wantErr string
+	}{
+		{
+			name: "urls",
+			json: `{
+				"urls": ["sfx.ogg", "sfx.mp3"],
+				"spritemap": {
+					"jump": {"start": 0, "end": 0.5},
+					"coin": {"start": 1.5, "end": 1.7504}
+				}
+			}`,
+			want: &AudioSprite{
+				Sources: []string{"sfx.ogg", "sfx.mp3"},
+				Sprites: map[string]Sprite{
+					"jump": {Duration: 500 * time.Millisecond},
+					"coin": {Offset: 1500 * time.Millisecond, Duration: 250 * time.Millisecond},
+				},
+			},
+		},
+		{
+			name: "resources and loop",
+			json: `{
+				"resources": ["music.webm"],
+				"spritemap": {"theme": {"start": 2, "end": 32, "loop": true}}
+			}`,
+			want: &AudioSprite{
+				Sources: []string{"music.webm"},
+				Sprites: map[string]Sprite{
+					"theme": {Offset: 2 * time.Second, Duration: 30 * time.Second, Loop: true},
+				},
+			},
+		},
+		{
+			name:    "negative start",
+			json:    `{"urls": ["sfx.mp3"], "spritemap": {"jump": {"start": -1, "end": 0.5}}}`,
+			wantErr: `sprite "jump" starts at negative time -1s`,
+		},
+		{
+			name:    "end before start",
+			json:    `{"urls": ["sfx.mp3"], "spritemap": {"jump": {"start": 2, "end": 1}}}`,
+			wantErr: `sprite "jump" ends at 1s before it starts at 2s`,
+		},
+		{
+			name:    "no sources",
+			json:    `{"spritemap": {}}`,
+			wantErr: "no urls or resources",
+		},
+		{
+			name:    "malformed",
+			json:    `{"urls": ["sfx.mp3"], "spritemap": {"jump": {"start": "zero"}}}`,
+			wantErr: "parsing audiosprite",
+		},
+		{
+			name:    "truncated",
+			json:    `{"urls": ["sfx.mp3"`,
+			wantErr: "parsing audiosprite",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAudioSprite(strings.NewReader(tt.json))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseAudioSprite() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(got.Sources, tt.want.Sources) {
+				t.Errorf("Sources = %v, want %v", got.Sources, tt.want.Sources)
+			}
+			if len(got.Sprites) != len(tt.want.Sprites) {
+				t.Errorf("Sprites = %v, want %v", got.Sprites, tt.want.Sprites)
+			}
+			for name, want := range tt.want.Sprites {
+				if got.Sprites[name] != want {
+					t.Errorf("Sprites[%q] = %+v, want %+v", name, got.Sprites[name], want)
+				}
+			}
+			if opts := got.Options(); !equalStrings(opts.Source, tt.want.Sources) || len(opts.Sprites) != len(tt.want.Sprites) {
+				t.Errorf("Options() = %+v, doesn't use the sources and sprites", opts)
+			}
+		})
+	}
+}
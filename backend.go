@@ -0,0 +1,121 @@
+package howler
+
+import "time"
+
+// Backend gives the package access to howler.js. Under js/wasm the default
+// backend calls into the real library through syscall/js; everywhere else it
+// is an in-memory FakeBackend.
+type Backend interface {
+	// Howler returns the global Howler object.
+	Howler() Value
+
+	// NewHowl constructs a Howl from an options object made with NewObject.
+	NewHowl(options Value) Value
+
+	// NewObject returns a new empty object.
+	NewObject() Value
+
+	// FuncOf returns a function that can be passed to the backend as a callback.
+	// It must be released once it is no longer needed.
+	FuncOf(fn func(this Value, args []Value)) Func
+
+	// AfterFunc calls fn in its own goroutine once d has passed on the
+	// backend's clock.
+	AfterFunc(d time.Duration, fn func()) Timer
+}
+
+// Timer is a call scheduled with Backend.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from happening, returning false if it already
+	// has or the timer was already stopped.
+	Stop() bool
+}
+
+// Value is a JavaScript value as seen through a Backend. Arguments passed to
+// Set and Call may be nil (null), booleans, numbers, strings, []any,
+// map[string]any, or a Value or Func from the same backend.
+type Value interface {
+	Get(key string) Value
+	Set(key string, value any)
+	Call(method string, args ...any) Value
+	Index(i int) Value
+	Length() int
+	// Keys returns the names of an object's own enumerable properties.
+	Keys() []string
+	Type() Type
+	Truthy() bool
+	Bool() bool
+	Int() int
+	Float() float64
+	String() string
+}
+
+// Func is a Go function that has been handed to a Backend.
+type Func interface {
+	Release()
+}
+
+// Type is the type of a Value.
+type Type int
+
+const (
+	TypeUndefined Type = iota
+	TypeNull
+	TypeBoolean
+	TypeNumber
+	TypeString
+	TypeSymbol
+	TypeObject
+	TypeFunction
+)
+
+var backend = defaultBackend()
+
+// SetBackend replaces the backend used by the package. Howls keep using the
+// backend they were created with, so this should be called before any are.
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// CurrentBackend returns the backend used by the package.
+func CurrentBackend() Backend {
+	return backend
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeNull:
+		return "null"
+	case TypeBoolean:
+		return "boolean"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeSymbol:
+		return "symbol"
+	case TypeObject:
+		return "object"
+	case TypeFunction:
+		return "function"
+	default:
+		return "undefined"
+	}
+}
+
+// nullValue is a JavaScript null that ignores whatever is done to it. It
+// stands in for the howler.js object of a Howl that couldn't be created.
+type nullValue struct{}
+
+func (nullValue) Get(key string) Value                  { return nullValue{} }
+func (nullValue) Set(key string, value any)             {}
+func (nullValue) Call(method string, args ...any) Value { return nullValue{} }
+func (nullValue) Index(i int) Value                     { return nullValue{} }
+func (nullValue) Length() int                           { return 0 }
+func (nullValue) Keys() []string                        { return nil }
+func (nullValue) Type() Type                            { return TypeNull }
+func (nullValue) Truthy() bool                          { return false }
+func (nullValue) Bool() bool                            { return false }
+func (nullValue) Int() int                              { return 0 }
+func (nullValue) Float() float64                        { return 0 }
+func (nullValue) String() string                        { return "null" }
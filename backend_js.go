@@ -0,0 +1,198 @@
+//go:build js && wasm
+
+package howler
+
+import (
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+func defaultBackend() Backend {
+	return jsBackend{}
+}
+
+// jsBackend talks to the real howler.js through syscall/js.
+type jsBackend struct{}
+
+func (jsBackend) Howler() Value {
+	return jsValue{js.Global().Get("Howler")}
+}
+
+func (jsBackend) NewHowl(options Value) Value {
+	return jsValue{js.Global().Get("Howl").New(toJS(options))}
+}
+
+func (jsBackend) NewObject() Value {
+	return jsValue{js.Global().Get("Object").New()}
+}
+
+func (jsBackend) FuncOf(fn func(this Value, args []Value)) Func {
+	return jsFunc{js.FuncOf(func(this js.Value, args []js.Value) any {
+		values := make([]Value, len(args))
+		for i, arg := range args {
+			values[i] = jsValue{arg}
+		}
+		fn(jsValue{this}, values)
+		return nil
+	})}
+}
+
+// AfterFunc schedules fn with setTimeout rather than a Go timer, so that it
+// runs after the events howler.js has already queued, which it also delivers
+// with setTimeout.
+func (jsBackend) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &jsTimer{}
+	t.fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		if t.stop() {
+			go fn()
+		}
+		return nil
+	})
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.id = js.Global().Call("setTimeout", t.fn, d.Seconds()*1000)
+	return t
+}
+
+// jsTimer is a call scheduled with setTimeout.
+type jsTimer struct {
+	mu   sync.Mutex
+	id   js.Value
+	fn   js.Func
+	done bool
+}
+
+func (t *jsTimer) Stop() bool {
+	if !t.stop() {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	js.Global().Call("clearTimeout", t.id)
+	return true
+}
+
+// stop marks the timer done and releases its function, returning false if it
+// already was.
+func (t *jsTimer) stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return false
+	}
+	t.done = true
+	t.fn.Release()
+	return true
+}
+
+type jsFunc struct {
+	fn js.Func
+}
+
+func (f jsFunc) Release() {
+	f.fn.Release()
+}
+
+type jsValue struct {
+	v js.Value
+}
+
+func (v jsValue) Get(key string) Value {
+	return jsValue{v.v.Get(key)}
+}
+
+func (v jsValue) Set(key string, value any) {
+	v.v.Set(key, toJS(value))
+}
+
+func (v jsValue) Call(method string, args ...any) Value {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		converted[i] = toJS(arg)
+	}
+	return jsValue{v.v.Call(method, converted...)}
+}
+
+func (v jsValue) Index(i int) Value {
+	return jsValue{v.v.Index(i)}
+}
+
+func (v jsValue) Length() int {
+	return v.v.Length()
+}
+
+func (v jsValue) Keys() []string {
+	keys := js.Global().Get("Object").Call("keys", v.v)
+	names := make([]string, keys.Length())
+	for i := range names {
+		names[i] = keys.Index(i).String()
+	}
+	return names
+}
+
+func (v jsValue) Type() Type {
+	switch v.v.Type() {
+	case js.TypeNull:
+		return TypeNull
+	case js.TypeBoolean:
+		return TypeBoolean
+	case js.TypeNumber:
+		return TypeNumber
+	case js.TypeString:
+		return TypeString
+	case js.TypeSymbol:
+		return TypeSymbol
+	case js.TypeObject:
+		return TypeObject
+	case js.TypeFunction:
+		return TypeFunction
+	default:
+		return TypeUndefined
+	}
+}
+
+func (v jsValue) Truthy() bool {
+	return v.v.Truthy()
+}
+
+func (v jsValue) Bool() bool {
+	return v.v.Bool()
+}
+
+func (v jsValue) Int() int {
+	return v.v.Int()
+}
+
+func (v jsValue) Float() float64 {
+	return v.v.Float()
+}
+
+func (v jsValue) String() string {
+	return v.v.String()
+}
+
+// toJS converts a value passed to the backend into something js.ValueOf
+// accepts.
+func toJS(x any) any {
+	switch x := x.(type) {
+	case jsValue:
+		return x.v
+	case jsFunc:
+		return x.fn
+	case []any:
+		arr := make([]any, len(x))
+		for i, v := range x {
+			arr[i] = toJS(v)
+		}
+		return arr
+	case map[string]any:
+		obj := make(map[string]any, len(x))
+		for k, v := range x {
+			obj[k] = toJS(v)
+		}
+		return obj
+	default:
+		return x
+	}
+}
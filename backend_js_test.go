@@ -0,0 +1,133 @@
+//go:build js && wasm
+
+package howler
+
+import (
+	"context"
+	"errors"
+	"syscall/js"
+	"testing"
+	"time"
+)
+
+// requireHowler loads cmd/test/howler.js into the global scope when the tests
+// run under node, which has no codecs, so every Howl fails to load.
+func requireHowler(t *testing.T) {
+	t.Helper()
+	if js.Global().Get("Howl").Truthy() {
+		return
+	}
+	require := js.Global().Get("require")
+	if require.Type() != js.TypeFunction {
+		t.Skip("howler.js can only be loaded under node")
+	}
+	// howler.js can't unload a Howl when there is no Audio at all, so give it
+	// an Audio that plays no formats.
+	js.Global().Get("Function").New(`globalThis.Audio = class {
+		constructor() { this.src = ""; this.paused = true; this.volume = 1; }
+		addEventListener() {}
+		removeEventListener() {}
+		canPlayType() { return ""; }
+		load() {}
+		play() { return Promise.resolve(); }
+		pause() {}
+	};`).Invoke()
+	dir := js.Global().Get("process").Call("cwd").String()
+	require.Invoke(dir + "/cmd/test/howler.js")
+	if !js.Global().Get("Howl").Truthy() {
+		t.Fatal("howler.js didn't define Howl")
+	}
+}
+
+// TestJSBackendSmoke runs the parts of the package that key or compare Howls
+// against the real howler.js, where a js.Value can't be used as a map key or
+// compared with ==.
+func TestJSBackendSmoke(t *testing.T) {
+	requireHowler(t)
+	SetBackend(defaultBackend())
+	t.Cleanup(func() {
+		SetVoiceLimit(VoiceLimit{})
+	})
+
+	h1 := New(HowlOptions{Source: []string{"one.mp3"}})
+	h2 := New(HowlOptions{Source: []string{"two.mp3"}})
+	defer h1.Unload()
+	defer h2.Unload()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{"load error", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var loadErr *LoadError
+			if err := h1.Wait(ctx); !errors.As(err, &loadErr) {
+				t.Fatalf("Wait() = %v without audio support, want a *LoadError", err)
+			}
+		}},
+		{"crossfade", func(t *testing.T) {
+			// howler.js holds fades back until a Howl loads, so only keying
+			// the Howls is tested here.
+			Crossfade(h1, h2, 10*time.Millisecond, nil)
+			if !isClosed(Crossfade(h1, h1, 10*time.Millisecond, nil)) {
+				t.Error("crossfade of a Howl into itself didn't finish at once")
+			}
+		}},
+		{"listeners", func(t *testing.T) {
+			base := LiveCallbacks()
+			sub := h1.On(EventPlay, func(Sound) {})
+			h1.Off(sub)
+			if got := LiveCallbacks(); got != base {
+				t.Errorf("LiveCallbacks() = %d after On and Off, want %d", got, base)
+			}
+		}},
+		{"ducker", func(t *testing.T) {
+			d := NewDucker([]Howl{h1}, []Sound{h2}, DuckerOptions{Amount: 0.5})
+			defer d.Close()
+			d.AddTrigger(h1)
+			d.AddTrigger(h2)
+			d.RemoveTrigger(h1)
+			d.AddTarget(h1)
+			d.RemoveTarget(h2)
+			if d.Ducked() {
+				t.Error("Ducked() = true with nothing playing")
+			}
+		}},
+		{"bus", func(t *testing.T) {
+			b := NewBus("music", nil)
+			b.Add(h1, h2, h1)
+			b.SetVolume(0.5)
+			b.Remove(h1)
+			if howls := b.Howls(); len(howls) != 1 {
+				t.Errorf("Howls() has %d Howls, want 1", len(howls))
+			}
+		}},
+		{"voice limit", func(t *testing.T) {
+			SetVoiceLimit(VoiceLimit{Max: 1})
+			h1.Play()
+			h2.Play()
+		}},
+		{"container", func(t *testing.T) {
+			c := NewSoundContainer([]ContainerEntry{{Howl: h1}, {Howl: h2}}, ContainerOptions{Pick: PickShuffle})
+			for range 4 {
+				c.Play()
+			}
+		}},
+		{"playlist", func(t *testing.T) {
+			p := NewPlaylist([]Track{
+				{Name: "one", Options: HowlOptions{Source: []string{"one.mp3"}}},
+				{Name: "two", Options: HowlOptions{Source: []string{"two.mp3"}}},
+			}, PlaylistOptions{})
+			defer p.Stop()
+			p.Play()
+			p.Next()
+			if i, _ := p.Current(); i != 1 {
+				t.Errorf("Current() = %d after Next, want 1", i)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !(js && wasm)
+
+package howler
+
+func defaultBackend() Backend {
+	return NewFakeBackend()
+}
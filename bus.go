@@ -0,0 +1,365 @@
+package howler
+
+import (
+	"sync"
+)
+
+// Bus is a mixer bus: a named group of Howls sharing a volume, mute and solo.
+// Buses form a tree, such as a master bus with music, sfx, voice and ui buses
+// below it, and the volumes of a Howl's bus and every bus above it multiply
+// together to scale the Howl's own volume.
+//
+// Volumes set on a Howl or its sounds, and the volumes they are faded between,
+// are their own volumes before any scaling, so Volume keeps reporting them
+// however the buses are set. Changing a bus reapplies the scaled volume of
+// every sound below it, including sounds that are fading.
+type Bus struct {
+	name     string
+	parent   *Bus
+	children []*Bus
+	volume   float64
+	muted    bool
+	solo     bool
+	howls    map[*soundStates]Howl
+	effects  []Effect
+}
+
+// mixer guards every Bus, so that changes can walk the whole tree.
+var mixer sync.Mutex
+
+// NewBus creates a bus below parent, or a root bus if parent is nil. Buses
+// start at full volume.
+func NewBus(name string, parent *Bus) *Bus {
+	b := &Bus{
+		name:   name,
+		parent: parent,
+		volume: 1,
+		howls:  make(map[*soundStates]Howl),
+	}
+	if parent != nil {
+		mixer.Lock()
+		parent.children = append(parent.children, b)
+		mixer.Unlock()
+	}
+	return b
+}
+
+// Name returns the name the bus was created with.
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// Parent returns the bus above b, or nil if b is a root bus.
+func (b *Bus) Parent() *Bus {
+	return b.parent
+}
+
+// Children returns the buses directly below b, in the order they were created.
+func (b *Bus) Children() []*Bus {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return append([]*Bus(nil), b.children...)
+}
+
+// Child returns the bus directly below b with the given name, or nil if there
+// isn't one.
+func (b *Bus) Child(name string) *Bus {
+	mixer.Lock()
+	defer mixer.Unlock()
+	for _, child := range b.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// Volume returns the bus's own volume, from 0 to 1.
+func (b *Bus) Volume() float64 {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return b.volume
+}
+
+// SetVolume sets the bus's own volume, from 0 to 1. Values out of range are
+// ignored, as they are by howler.js.
+func (b *Bus) SetVolume(volume float64) {
+	if volume < 0 || volume > 1 {
+		return
+	}
+	mixer.Lock()
+	defer mixer.Unlock()
+	b.volume = volume
+	b.apply()
+}
+
+// Muted returns true if the bus itself is muted.
+func (b *Bus) Muted() bool {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return b.muted
+}
+
+// SetMuted mutes or unmutes the bus, silencing every Howl on it and on the
+// buses below it. Muting a bus doesn't change the mute state of its Howls.
+func (b *Bus) SetMuted(muted bool) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	b.muted = muted
+	b.apply()
+}
+
+// Soloed returns true if the bus itself is soloed.
+func (b *Bus) Soloed() bool {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return b.solo
+}
+
+// SetSolo solos or unsolos the bus. While any bus in a tree is soloed, only the
+// Howls on soloed buses and the buses below them are heard.
+func (b *Bus) SetSolo(solo bool) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	b.solo = solo
+	b.root().apply()
+}
+
+// Gain returns the volume the bus scales its Howls' volumes by, taking every
+// bus above it into account. It is 0 while the bus is muted or silenced by a
+// solo.
+func (b *Bus) Gain() float64 {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return b.gain()
+}
+
+// Add assigns howls to the bus, moving them from the bus they were on.
+func (b *Bus) Add(howls ...Howl) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	gain := b.gain()
+	for _, h := range howls {
+		if old := h.states.bus; old != nil {
+			delete(old.howls, h.states)
+		}
+		b.howls[h.states] = h
+		h.states.join(h.value, b, gain)
+		h.states.reroute(h.value)
+	}
+}
+
+// Remove takes howls off the bus, restoring them to their own volumes. Howls
+// on other buses are left alone.
+func (b *Bus) Remove(howls ...Howl) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	for _, h := range howls {
+		if h.states.bus == b {
+			delete(b.howls, h.states)
+			h.states.leave(h.value)
+			h.states.reroute(h.value)
+		}
+	}
+}
+
+// Howls returns the Howls assigned to the bus itself, not including those on
+// the buses below it.
+func (b *Bus) Howls() []Howl {
+	mixer.Lock()
+	defer mixer.Unlock()
+	howls := make([]Howl, 0, len(b.howls))
+	for _, h := range b.howls {
+		howls = append(howls, h)
+	}
+	return howls
+}
+
+// Bus returns the bus the Howl is assigned to, or nil if it isn't on one.
+func (h Howl) Bus() *Bus {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return h.states.bus
+}
+
+// unassign takes h off its bus without touching its volume, for Howls being
+// unloaded.
+func unassign(h Howl) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	if b := h.states.bus; b != nil {
+		delete(b.howls, h.states)
+		h.states.leave(nil)
+	}
+}
+
+func (b *Bus) root() *Bus {
+	for b.parent != nil {
+		b = b.parent
+	}
+	return b
+}
+
+// soloing returns true if b or any bus below it is soloed.
+func (b *Bus) soloing() bool {
+	if b.solo {
+		return true
+	}
+	for _, child := range b.children {
+		if child.soloing() {
+			return true
+		}
+	}
+	return false
+}
+
+// gain returns the volume b scales its Howls' volumes by. The caller must hold
+// mixer.
+func (b *Bus) gain() float64 {
+	heard := !b.root().soloing()
+	gain := 1.0
+	for bus := b; bus != nil; bus = bus.parent {
+		if bus.muted {
+			return 0
+		}
+		heard = heard || bus.solo
+		gain *= bus.volume
+	}
+	if !heard {
+		return 0
+	}
+	return gain
+}
+
+// apply reapplies the gain of b and every bus below it to their Howls. The
+// caller must hold mixer.
+func (b *Bus) apply() {
+	gain := b.gain()
+	for _, h := range b.howls {
+		h.states.regain(h.value, gain)
+	}
+	for _, child := range b.children {
+		child.apply()
+	}
+}
+
+// join starts scaling the volumes of howl by gain for bus, taking its current
+// volumes as its own.
+func (s *soundStates) join(howl Value, bus *Bus, gain float64) {
+	s.mu.Lock()
+	tracked := s.bus != nil
+	s.mu.Unlock()
+
+	if !tracked {
+		volume := howl.Call("volume").Float()
+		volumes := make(map[int]float64)
+		for _, id := range soundIDs(howl) {
+			if v := howl.Call("volume", id).Float(); v != volume {
+				volumes[id] = v
+			}
+		}
+
+		s.mu.Lock()
+		s.gain = 1
+		s.volume = volume
+		s.volumes = volumes
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	s.bus = bus
+	s.mu.Unlock()
+	s.regain(howl, gain)
+}
+
+// leave stops scaling the volumes of howl, restoring them to its own volumes
+// unless howl is nil.
+func (s *soundStates) leave(howl Value) {
+	if howl != nil {
+		s.regain(howl, 1)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bus = nil
+	s.volumes = nil
+}
+
+// regain scales the volumes of howl by gain instead of the gain it had.
+func (s *soundStates) regain(howl Value, gain float64) {
+	s.mu.Lock()
+	if s.gain == gain {
+		s.mu.Unlock()
+		return
+	}
+	s.gain = gain
+	volume := s.volume
+	volumes := make(map[int]float64, len(s.volumes))
+	for id, v := range s.volumes {
+		volumes[id] = v
+	}
+	s.mu.Unlock()
+
+	// Setting the Howl's volume sets every sound's, so the sounds with volumes
+	// of their own are set again afterwards.
+	howl.Call("volume", volume*gain)
+	for id, v := range volumes {
+		howl.Call("volume", v*gain, id, true)
+	}
+}
+
+// getVolume returns the own volume of sound id, or of the Howl if id is -1.
+func (s *soundStates) getVolume(howl Value, id int) float64 {
+	if s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.bus != nil {
+			if v, ok := s.volumes[id]; ok {
+				return v
+			}
+			return s.volume
+		}
+	}
+	if id < 0 {
+		return howl.Call("volume").Float()
+	}
+	return howl.Call("volume", id).Float()
+}
+
+// setVolume sets the own volume of sound id, or of the Howl and every sound if
+// id is -1, scaled by the gain of its buses. Unless internal is true, fades
+// howler.js is running on the sounds are stopped.
+func (s *soundStates) setVolume(howl Value, id int, volume float64, internal bool) {
+	v := volume
+	if s != nil && volume >= 0 && volume <= 1 {
+		s.mu.Lock()
+		if s.bus != nil {
+			if id < 0 {
+				s.volume = volume
+				s.volumes = make(map[int]float64)
+			} else {
+				s.volumes[id] = volume
+			}
+			v *= s.gain
+		}
+		s.mu.Unlock()
+	}
+
+	switch {
+	case id < 0:
+		howl.Call("volume", v)
+	case internal:
+		howl.Call("volume", v, id, true)
+	default:
+		howl.Call("volume", v, id)
+	}
+}
+
+// mixed returns true if the Howl is on a bus.
+func (s *soundStates) mixed() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bus != nil
+}
@@ -0,0 +1,127 @@
+package howler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBusGain(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	master := NewBus("master", nil)
+	music := NewBus("music", master)
+	sfx := NewBus("sfx", master)
+	if master.Child("music") != music || master.Child("voice") != nil || len(master.Children()) != 2 {
+		t.Fatal("bus tree isn't as created")
+	}
+
+	song := New(HowlOptions{Source: []string{"a.mp3"}, Volume: Some(0.5)})
+	shot := New(HowlOptions{Source: []string{"a.mp3"}})
+	songSound := song.Play()
+	b.Flush()
+	music.Add(song)
+	sfx.Add(shot)
+	shotSound := shot.Play()
+	b.Flush()
+
+	// actual returns the volume howler.js is playing sound at.
+	actual := func(s Sound) float64 {
+		s1 := s.(soundSpecific)
+		return s1.value.Call("volume", s1.id).Float()
+	}
+
+	tests := []struct {
+		name       string
+		change     func()
+		music, sfx float64
+	}{
+		{"initial", func() {}, 1, 1},
+		{"master volume", func() { master.SetVolume(0.5) }, 0.5, 0.5},
+		{"music volume", func() { music.SetVolume(0.4) }, 0.2, 0.5},
+		{"mute sfx", func() { sfx.SetMuted(true) }, 0.2, 0},
+		{"unmute sfx", func() { sfx.SetMuted(false) }, 0.2, 0.5},
+		{"solo music", func() { music.SetSolo(true) }, 0.2, 0},
+		{"solo master", func() { music.SetSolo(false); master.SetSolo(true) }, 0.2, 0.5},
+		{"mute master", func() { master.SetSolo(false); master.SetMuted(true) }, 0, 0},
+		{"out of range", func() { master.SetMuted(false); master.SetVolume(2) }, 0.2, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			if got := music.Gain(); math.Abs(got-tt.music) > 1e-9 {
+				t.Errorf("music gain = %v, want %v", got, tt.music)
+			}
+			if got := actual(songSound); math.Abs(got-0.5*tt.music) > 1e-9 {
+				t.Errorf("song plays at %v, want %v", got, 0.5*tt.music)
+			}
+			if got := actual(shotSound); math.Abs(got-tt.sfx) > 1e-9 {
+				t.Errorf("shot plays at %v, want %v", got, tt.sfx)
+			}
+			// Volume reports the sound's own volume, whatever its buses do.
+			if songSound.Volume() != 0.5 || shotSound.Volume() != 1 {
+				t.Errorf("own volumes = %v, %v; want 0.5, 1", songSound.Volume(), shotSound.Volume())
+			}
+		})
+	}
+}
+
+func TestBusMembership(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	one := NewBus("one", nil)
+	two := NewBus("two", nil)
+	one.SetVolume(0.5)
+	two.SetVolume(0.25)
+
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	s := h.Play()
+	b.Flush()
+	actual := func() float64 { return h.value.Call("volume", s.ID()).Float() }
+
+	one.Add(h)
+	if h.Bus() != one || actual() != 0.5 {
+		t.Errorf("on one: bus, volume = %v, %v; want one, 0.5", h.Bus().Name(), actual())
+	}
+	// A sound's own volume is scaled too, and set volumes are kept as its own.
+	s.SetVolume(0.8)
+	if actual() != 0.4 || s.Volume() != 0.8 {
+		t.Errorf("after SetVolume: actual, own = %v, %v; want 0.4, 0.8", actual(), s.Volume())
+	}
+	two.Add(h)
+	if len(one.Howls()) != 0 || len(two.Howls()) != 1 || actual() != 0.2 {
+		t.Errorf("moved to two: %d, %d howls, volume %v; want 0, 1, 0.2", len(one.Howls()), len(two.Howls()), actual())
+	}
+	one.Remove(h)
+	if h.Bus() != two {
+		t.Error("removing from a bus it isn't on moved the Howl")
+	}
+	two.Remove(h)
+	if h.Bus() != nil || actual() != 0.8 {
+		t.Errorf("off buses: bus, volume = %v, %v; want nil, 0.8", h.Bus(), actual())
+	}
+}
+
+func TestBusFade(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	bus := NewBus("music", nil)
+	bus.SetVolume(0.5)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	bus.Add(h)
+	s := h.Play()
+	b.Flush()
+
+	done := s.Fade(0, 1, time.Second)
+	b.Advance(500 * time.Millisecond)
+	// Fades on a bus follow changes to the bus while they run.
+	bus.SetVolume(1)
+	b.Advance(250 * time.Millisecond)
+	if got := h.value.Call("volume", s.ID()).Float(); math.Abs(got-0.75) > 0.02 {
+		t.Errorf("volume three quarters through = %v, want 0.75", got)
+	}
+	b.Advance(250 * time.Millisecond)
+	if !isClosed(done) || s.Volume() != 1 {
+		t.Errorf("fade finished, volume = %v, %v; want true, 1", isClosed(done), s.Volume())
+	}
+}
@@ -0,0 +1,228 @@
+package howler
+
+// AudioContext returns the Web Audio context howler.js plays through
+// (Howler.ctx), or nil if Web Audio isn't in use. howler.js creates it along
+// with the first Howl, and replaces it when Unload is called.
+func AudioContext() Value {
+	if ctx := backend.Howler().Get("ctx"); ctx.Truthy() {
+		return ctx
+	}
+	return nil
+}
+
+// MasterGain returns the gain node every sound ends up at before reaching the
+// speakers (Howler.masterGain), or nil if Web Audio isn't in use.
+func MasterGain() Value {
+	if gain := backend.Howler().Get("masterGain"); gain.Truthy() {
+		return gain
+	}
+	return nil
+}
+
+// SoundNode returns the gain node of a single sound played by a Howl (its
+// _node), where the sound's signal leaves howler.js. It returns nil for a Howl,
+// an invalid Sound, a sound that has been recycled, or a Howl using HTML5
+// Audio.
+func SoundNode(sound Sound) Value {
+	if s, ok := sound.(soundSpecific); ok {
+		return s.node()
+	}
+	return nil
+}
+
+// node returns the sound's gain node, or nil if it has none.
+func (s soundSpecific) node() Value {
+	if s.value == nil || !webAudio(s.value) {
+		return nil
+	}
+	return soundNode(s.value, s.id)
+}
+
+// Effect is a stage of Web Audio processing that can be inserted into the
+// signal chain of a Howl or Bus with SetEffects. The signal enters through
+// Input and leaves through Output, which may be the same node.
+//
+// Effects belong to the AudioContext they were created in, so they must be
+// created again after Unload. An Effect can only be in one chain at a time.
+type Effect interface {
+	Input() Value
+	Output() Value
+}
+
+// Effects returns the effects the Howl's sounds pass through.
+func (h Howl) Effects() []Effect {
+	h.states.mu.Lock()
+	defer h.states.mu.Unlock()
+	return append([]Effect(nil), h.states.effects...)
+}
+
+// SetEffects passes the Howl's sounds through effects, in order, before they
+// reach the Howl's bus. Passing no effects removes the chain. It fails with
+// ErrNoWebAudio if the Howl uses HTML5 Audio.
+func (h Howl) SetEffects(effects ...Effect) error {
+	if len(effects) > 0 && !webAudio(h.value) {
+		return ErrNoWebAudio
+	}
+	mixer.Lock()
+	defer mixer.Unlock()
+	h.states.mu.Lock()
+	old := h.states.effects
+	h.states.effects = append([]Effect(nil), effects...)
+	h.states.mu.Unlock()
+
+	unchain(old)
+	h.states.reroute(h.value)
+	return nil
+}
+
+// Effects returns the effects the Howls on the bus and the buses below it pass
+// through.
+func (b *Bus) Effects() []Effect {
+	mixer.Lock()
+	defer mixer.Unlock()
+	return append([]Effect(nil), b.effects...)
+}
+
+// SetEffects passes every Howl on the bus and the buses below it through
+// effects, in order, before they reach the bus above. Passing no effects
+// removes the chain. Howls using HTML5 Audio aren't affected.
+func (b *Bus) SetEffects(effects ...Effect) {
+	mixer.Lock()
+	defer mixer.Unlock()
+	old := b.effects
+	b.effects = append([]Effect(nil), effects...)
+
+	unchain(old)
+	chain(b.effects, b.parent.input())
+	b.reroute()
+}
+
+// input returns the node the Howls on b are connected to: the start of the
+// nearest effect chain at or above b, or the master gain if there is none.
+// The caller must hold mixer.
+func (b *Bus) input() Value {
+	for bus := b; bus != nil; bus = bus.parent {
+		if len(bus.effects) > 0 {
+			return bus.effects[0].Input()
+		}
+	}
+	return MasterGain()
+}
+
+// reroute reconnects everything feeding into b to its input after it has
+// changed. The caller must hold mixer.
+func (b *Bus) reroute() {
+	for _, h := range b.howls {
+		h.states.reroute(h.value)
+	}
+	for _, child := range b.children {
+		if len(child.effects) > 0 {
+			chain(child.effects, b.input())
+		} else {
+			child.reroute()
+		}
+	}
+}
+
+// input returns the node the Howl's sounds are connected to. The caller must
+// hold mixer.
+func (s *soundStates) input() Value {
+	if len(s.effects) > 0 {
+		return s.effects[0].Input()
+	}
+	return s.bus.input()
+}
+
+// reroute connects the Howl's effect chain and every sound it holds to where
+// they now lead. The caller must hold mixer.
+func (s *soundStates) reroute(howl Value) {
+	if !webAudio(howl) {
+		return
+	}
+	chain(s.effects, s.bus.input())
+	input := s.input()
+	for _, id := range soundIDs(howl) {
+		connect(soundNode(howl, id), input)
+	}
+}
+
+// route connects a sound that has just been played to the Howl's effect chain
+// or bus, as howler.js connects new sounds straight to the master gain.
+func (s *soundStates) route(howl Value, id int) {
+	if s == nil {
+		return
+	}
+	mixer.Lock()
+	defer mixer.Unlock()
+	if (len(s.effects) > 0 || s.bus.chained()) && webAudio(howl) {
+		connect(soundNode(howl, id), s.input())
+	}
+}
+
+// unchain disconnects the Howl's effect chain, for Howls being unloaded.
+func (s *soundStates) unchain() {
+	mixer.Lock()
+	defer mixer.Unlock()
+	s.mu.Lock()
+	effects := s.effects
+	s.effects = nil
+	s.mu.Unlock()
+	unchain(effects)
+}
+
+// chained returns true if b or a bus above it has effects. The caller must
+// hold mixer.
+func (b *Bus) chained() bool {
+	for bus := b; bus != nil; bus = bus.parent {
+		if len(bus.effects) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// chain connects effects one after another, with the last leading to dest.
+func chain(effects []Effect, dest Value) {
+	for i, e := range effects {
+		if i+1 < len(effects) {
+			connect(e.Output(), effects[i+1].Input())
+		} else {
+			connect(e.Output(), dest)
+		}
+	}
+}
+
+// unchain disconnects the outputs of effects.
+func unchain(effects []Effect) {
+	for _, e := range effects {
+		e.Output().Call("disconnect")
+	}
+}
+
+// connect makes dest the only node node leads to. Nil nodes are ignored.
+func connect(node, dest Value) {
+	if node == nil || dest == nil {
+		return
+	}
+	node.Call("disconnect")
+	node.Call("connect", dest)
+}
+
+// webAudio returns true if howl plays through Web Audio rather than HTML5
+// Audio.
+func webAudio(howl Value) bool {
+	return howl.Get("_webAudio").Truthy()
+}
+
+// soundNode returns the gain node of sound id of howl, or nil if the sound
+// doesn't exist.
+func soundNode(howl Value, id int) Value {
+	sound := howl.Call("_soundById", id)
+	if !sound.Truthy() {
+		return nil
+	}
+	if node := sound.Get("_node"); node.Truthy() {
+		return node
+	}
+	return nil
+}
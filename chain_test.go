@@ -0,0 +1,122 @@
+package howler
+
+import (
+	"errors"
+	"testing"
+)
+
+// pathOf returns the nodes along the signal path of sound, naming the effects
+// in names and leaving other nodes unnamed.
+func pathOf(b *FakeBackend, sound Sound, names map[Value]string) []string {
+	var path []string
+	for _, node := range b.SignalPath(sound) {
+		path = append(path, names[node])
+	}
+	return path
+}
+
+func TestEffectChains(t *testing.T) {
+	b := newTestBackend(t)
+	newFilter := func() *BiquadFilter {
+		f, err := NewBiquadFilter(BiquadOptions{Type: FilterLowpass})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	howlFX, busFX, childFX := newFilter(), newFilter(), newFilter()
+	comp, err := NewCompressor(CompressorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[Value]string{
+		MasterGain():                      "master",
+		AudioContext().Get("destination"): "speakers",
+		howlFX.Node():                     "howl",
+		busFX.Node():                      "bus",
+		childFX.Node():                    "child",
+		comp.Node():                       "compressor",
+	}
+
+	bus := NewBus("sfx", nil)
+	child := NewBus("weapons", bus)
+	h := New(HowlOptions{Source: []string{"a.mp3"}, Loop: Some(true)})
+	playing := h.Play()
+	b.Flush()
+
+	tests := []struct {
+		name   string
+		change func()
+		want   []string
+	}{
+		{"no effects", func() {}, []string{"master", "speakers"}},
+		{"howl effect", func() { h.SetEffects(howlFX) }, []string{"howl", "master", "speakers"}},
+		{"on bus", func() { child.Add(h) }, []string{"howl", "master", "speakers"}},
+		{"bus effect", func() { bus.SetEffects(busFX) }, []string{"howl", "bus", "master", "speakers"}},
+		{"child bus effects", func() { child.SetEffects(childFX, comp) }, []string{"howl", "child", "compressor", "bus", "master", "speakers"}},
+		{"howl effect removed", func() { h.SetEffects() }, []string{"child", "compressor", "bus", "master", "speakers"}},
+		{"bus effect removed", func() { bus.SetEffects() }, []string{"child", "compressor", "master", "speakers"}},
+		{"off bus", func() { child.Remove(h) }, []string{"master", "speakers"}},
+		{"back on bus", func() { child.Add(h) }, []string{"child", "compressor", "master", "speakers"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			if got := pathOf(b, playing, names); !equalStrings(got, tt.want) {
+				t.Errorf("playing sound goes through %q, want %q", got, tt.want)
+			}
+			// Sounds played after the change are routed the same way.
+			sound := h.Play()
+			b.Flush()
+			if got := pathOf(b, sound, names); !equalStrings(got, tt.want) {
+				t.Errorf("new sound goes through %q, want %q", got, tt.want)
+			}
+			sound.Stop()
+		})
+	}
+
+	h.Unload()
+	if len(h.Effects()) != 0 {
+		t.Errorf("Effects() after Unload = %v, want none", h.Effects())
+	}
+}
+
+func TestEffectsHTML5(t *testing.T) {
+	newTestBackend(t)
+	f, err := NewBiquadFilter(BiquadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(HowlOptions{Source: []string{"a.mp3"}, HTML5: Some(true)})
+	if err := h.SetEffects(f); !errors.Is(err, ErrNoWebAudio) {
+		t.Errorf("SetEffects() = %v, want %v", err, ErrNoWebAudio)
+	}
+	if SoundNode(h.Play()) != nil {
+		t.Error("SoundNode of an HTML5 sound isn't nil")
+	}
+}
+
+func TestSoundNode(t *testing.T) {
+	b := newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	sound := h.Play()
+	b.Flush()
+
+	tests := []struct {
+		name  string
+		sound Sound
+		want  bool
+	}{
+		{"sound", sound, true},
+		{"howl", h, false},
+		{"invalid", invalidSound{ErrNotPlayed}, false},
+		{"unknown id", soundSpecific{id: 1, value: h.value, states: h.states}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SoundNode(tt.sound) != nil; got != tt.want {
+				t.Errorf("SoundNode() != nil is %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Command howlsprite builds an audio sprite sheet from a directory of PCM WAV
+// files. The files are joined in name order with silence between them into a
+// single WAV file, and a sound manifest describing where each one ended up is
+// written alongside it, ready for howler.LoadManifest.
+//
+// Usage:
+//
+//	howlsprite [flags] dir
+//
+// Every file must share the same sample rate, bit depth and channel count.
+// Each sprite is named after its file without the extension.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	howler "github.com/medievalsoftware/go-howler.js"
+)
+
+func main() {
+	output := flag.String("o", "sprite.wav", "the sprite sheet WAV file to write")
+	manifest := flag.String("manifest", "", "the manifest to write (default: the output path with a .json extension, - for stdout)")
+	name := flag.String("name", "", "the name of the sound in the manifest (default: the output file name without its extension)")
+	src := flag.String("src", "", "the source URL of the sprite sheet in the manifest (default: the output file name)")
+	gap := flag.Duration("gap", time.Second, "the silence between sprites")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: howlsprite [flags] dir\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || *gap < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	base := filepath.Base(*output)
+	if *manifest == "" {
+		*manifest = strings.TrimSuffix(*output, filepath.Ext(*output)) + ".json"
+	}
+	if *name == "" {
+		*name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if *src == "" {
+		*src = base
+	}
+
+	if err := run(flag.Arg(0), *output, *manifest, *name, *src, *gap); err != nil {
+		fmt.Fprintln(os.Stderr, "howlsprite:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output, manifestPath, name, src string, gap time.Duration) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.[wW][aA][vV]"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no WAV files in %s", dir)
+	}
+	sort.Strings(paths)
+
+	sheet, sprites, err := build(paths, gap)
+	if err != nil {
+		return err
+	}
+
+	manifest := howler.Manifest{
+		Sounds: map[string]howler.ManifestSound{
+			name: {
+				Sources: []string{src},
+				Sprites: sprites,
+			},
+		},
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if err := writeFile(output, func(w io.Writer) error {
+		return writeWAV(w, sheet.format, sheet.data)
+	}); err != nil {
+		return err
+	}
+	if manifestPath == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(manifestPath, data, 0o644)
+}
+
+// build joins the WAV files at paths with gap silence between them, returning
+// the sprite sheet and the sprite for each file.
+func build(paths []string, gap time.Duration) (*wav, map[string]howler.ManifestSprite, error) {
+	var sheet wav
+	var buf bytes.Buffer
+	sprites := make(map[string]howler.ManifestSprite, len(paths))
+
+	for i, path := range paths {
+		w, err := readWAV(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == 0 {
+			sheet.format = w.format
+		} else if w.format != sheet.format {
+			return nil, nil, fmt.Errorf("%s: format %s doesn't match %s", path, w.format, sheet.format)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if _, ok := sprites[name]; ok {
+			return nil, nil, fmt.Errorf("%s: duplicate sprite name %q", path, name)
+		}
+
+		if i > 0 {
+			frames := int(gap.Seconds()*float64(sheet.format.SampleRate) + 0.5)
+			buf.Write(bytes.Repeat([]byte{sheet.format.silence()}, frames*sheet.format.frameSize()))
+		}
+
+		start := buf.Len() / sheet.format.frameSize()
+		buf.Write(w.data)
+		end := buf.Len() / sheet.format.frameSize()
+
+		// Round the end rather than the duration so rounding errors don't build
+		// up across the sheet.
+		offset := millis(start, sheet.format.SampleRate)
+		sprites[name] = howler.ManifestSprite{
+			Offset:   offset,
+			Duration: millis(end, sheet.format.SampleRate) - offset,
+		}
+	}
+
+	sheet.data = buf.Bytes()
+	return &sheet, sprites, nil
+}
+
+// millis converts a number of frames to milliseconds, rounded to the nearest
+// millisecond.
+func millis(frames, rate int) int64 {
+	return (int64(frames)*1000 + int64(rate)/2) / int64(rate)
+}
+
+// writeFile creates the file at path and writes it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	formatPCM        = 1
+	formatExtensible = 0xFFFE
+)
+
+// format is the sample format of a PCM WAV file.
+type format struct {
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+}
+
+func (f format) String() string {
+	return fmt.Sprintf("%d-bit %d Hz %d channel", f.BitsPerSample, f.SampleRate, f.Channels)
+}
+
+// frameSize returns the number of bytes in one sample for every channel.
+func (f format) frameSize() int {
+	return f.Channels * ((f.BitsPerSample + 7) / 8)
+}
+
+// silence returns the byte a silent sample is made of. 8-bit samples are
+// unsigned, so silence sits in the middle of their range.
+func (f format) silence() byte {
+	if f.BitsPerSample <= 8 {
+		return 0x80
+	}
+	return 0
+}
+
+// wav is a decoded PCM WAV file.
+type wav struct {
+	format format
+	data   []byte
+}
+
+// frames returns the number of frames of audio in the file.
+func (w *wav) frames() int {
+	return len(w.data) / w.format.frameSize()
+}
+
+// readWAV reads the PCM WAV file at path.
+func readWAV(path string) (*wav, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	w, err := decodeWAV(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// decodeWAV decodes a RIFF WAVE stream holding PCM samples.
+func decodeWAV(r io.Reader) (*wav, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+
+	var w wav
+	var haveFormat, haveData bool
+	for !haveData {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("no data chunk")
+			}
+			return nil, fmt.Errorf("reading chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			body, err := readChunk(r, size)
+			if err != nil {
+				return nil, fmt.Errorf("reading fmt chunk: %w", err)
+			}
+			f, err := decodeFormat(body)
+			if err != nil {
+				return nil, err
+			}
+			w.format = f
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("data chunk before fmt chunk")
+			}
+			data, err := readChunk(r, size)
+			if err != nil {
+				return nil, fmt.Errorf("reading data chunk: %w", err)
+			}
+			w.data = data
+			// Drop any trailing partial frame.
+			w.data = w.data[:w.frames()*w.format.frameSize()]
+			haveData = true
+		default:
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("skipping %q chunk: %w", id, err)
+			}
+		}
+
+		// Chunks are padded to an even number of bytes.
+		if size%2 == 1 && !haveData {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				return nil, fmt.Errorf("reading chunk padding: %w", err)
+			}
+		}
+	}
+	return &w, nil
+}
+
+// readChunk reads the size bytes of a chunk's body. The size comes from the
+// file, so rather than trusting it to allocate the body up front, the body is
+// read into a buffer that grows as it goes, and a file that ends early fails
+// once it runs out.
+func readChunk(r io.Reader, size int64) ([]byte, error) {
+	var buf bytes.Buffer
+	if n, err := io.CopyN(&buf, r, size); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: chunk is %d bytes but the file ends after %d", io.ErrUnexpectedEOF, size, n)
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeFormat decodes the body of a fmt chunk.
+func decodeFormat(body []byte) (format, error) {
+	if len(body) < 16 {
+		return format{}, errors.New("fmt chunk too short")
+	}
+	tag := binary.LittleEndian.Uint16(body[0:2])
+	if tag == formatExtensible && len(body) >= 26 {
+		// The real format is the first two bytes of the sub-format GUID.
+		tag = binary.LittleEndian.Uint16(body[24:26])
+	}
+	if tag != formatPCM {
+		return format{}, fmt.Errorf("unsupported format %#x, only PCM is supported", tag)
+	}
+
+	f := format{
+		Channels:      int(binary.LittleEndian.Uint16(body[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(body[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(body[14:16])),
+	}
+	if f.Channels == 0 || f.SampleRate == 0 || f.BitsPerSample == 0 {
+		return format{}, fmt.Errorf("invalid format %s", f)
+	}
+	return f, nil
+}
+
+// writeWAV writes a PCM WAV file holding data to w.
+func writeWAV(w io.Writer, f format, data []byte) error {
+	const headerSize = 44
+	// The data chunk is padded to an even number of bytes, and the padding
+	// counts towards the size of the RIFF chunk.
+	pad := len(data) % 2
+	riffSize := uint64(headerSize-8) + uint64(len(data)) + uint64(pad)
+	if riffSize > 1<<32-1 {
+		return errors.New("audio too long for a WAV file")
+	}
+
+	frameSize := f.frameSize()
+	header := make([]byte, headerSize)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(riffSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], formatPCM)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(f.Channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(f.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(f.SampleRate*frameSize))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(frameSize))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(f.BitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if pad == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
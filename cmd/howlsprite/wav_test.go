@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+func TestWriteWAV(t *testing.T) {
+	tests := []struct {
+		name   string
+		format format
+		data   []byte
+	}{
+		{"16-bit stereo", format{Channels: 2, SampleRate: 44100, BitsPerSample: 16}, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{"8-bit mono odd length", format{Channels: 1, SampleRate: 8000, BitsPerSample: 8}, []byte{0x80, 0x81, 0x82}},
+		{"empty", format{Channels: 1, SampleRate: 22050, BitsPerSample: 16}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeWAV(&buf, tt.format, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.Bytes()
+			if len(out)%2 != 0 {
+				t.Errorf("file is %d bytes, want an even number", len(out))
+			}
+			if got, want := int(binary.LittleEndian.Uint32(out[4:8])), len(out)-8; got != want {
+				t.Errorf("RIFF size = %d, want %d", got, want)
+			}
+
+			w, err := decodeWAV(bytes.NewReader(out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.format != tt.format {
+				t.Errorf("format = %v, want %v", w.format, tt.format)
+			}
+			if !bytes.Equal(w.data, tt.data) {
+				t.Errorf("data = %v, want %v", w.data, tt.data)
+			}
+		})
+	}
+}
+
+func TestDecodeWAVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"empty", nil},
+		{"not RIFF", []byte("RIFX\x00\x00\x00\x00WAVE")},
+		{"no data", []byte("RIFF\x04\x00\x00\x00WAVE")},
+		{"data before fmt", append([]byte("RIFF\x0c\x00\x00\x00WAVEdata"), 0, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeWAV(bytes.NewReader(tt.input)); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestDecodeWAVOversizedChunk(t *testing.T) {
+	var fmtBody bytes.Buffer
+	binary.Write(&fmtBody, binary.LittleEndian, []uint16{formatPCM, 1})
+	binary.Write(&fmtBody, binary.LittleEndian, []uint32{8000, 8000})
+	binary.Write(&fmtBody, binary.LittleEndian, []uint16{1, 8})
+	chunk := func(id string, size uint32, body []byte) []byte {
+		b := append([]byte(id), 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[4:], size)
+		return append(b, body...)
+	}
+	header := []byte("RIFF\xff\xff\xff\xffWAVE")
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"fmt", concat(header, chunk("fmt ", 0xFFFFFFFF, fmtBody.Bytes()))},
+		{"data", concat(header, chunk("fmt ", 16, fmtBody.Bytes()), chunk("data", 0xFFFFFFFF, []byte{1, 2, 3}))},
+		{"truncated data", concat(header, chunk("fmt ", 16, fmtBody.Bytes()), chunk("data", 100, []byte{1, 2, 3}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, err := decodeWAV(bytes.NewReader(tt.input))
+			runtime.ReadMemStats(&after)
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("decodeWAV() = %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+			if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+				t.Errorf("decodeWAV() allocated %d bytes for a %d byte file", n, len(tt.input))
+			}
+		})
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
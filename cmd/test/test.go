@@ -22,9 +22,9 @@ func main() {
 	var howl howler.Howl
 
 	howl = howler.New(howler.HowlOptions{
-		Source:   []any{"cheeky-buggers.mp3"},
-		Volume:   0.5,
-		Autoplay: false,
+		Source:   []string{"cheeky-buggers.mp3"},
+		Volume:   howler.Some(0.5),
+		Autoplay: howler.Some(false),
 
 		OnLoad: func() {
 			progress.Set("value", 0)
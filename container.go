@@ -0,0 +1,226 @@
+package howler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// PickMode sets how a SoundContainer chooses the entry to play.
+type PickMode int
+
+const (
+	// PickRandom picks any entry, so the same one may play twice in a row.
+	PickRandom PickMode = iota
+	// PickShuffle plays every entry once in a random order before shuffling
+	// them again, never playing the same entry twice in a row.
+	PickShuffle
+	// PickSequential plays the entries in order, starting again from the first
+	// after the last.
+	PickSequential
+	// PickWeighted picks entries at random in proportion to their Weight.
+	PickWeighted
+)
+
+func (m PickMode) String() string {
+	switch m {
+	case PickRandom:
+		return "random"
+	case PickShuffle:
+		return "shuffle"
+	case PickSequential:
+		return "sequential"
+	case PickWeighted:
+		return "weighted"
+	default:
+		return fmt.Sprintf("PickMode(%d)", int(m))
+	}
+}
+
+// ContainerEntry is a sound a SoundContainer can play.
+type ContainerEntry struct {
+	Howl Howl
+	// Sprite is the sprite to play. An empty sprite plays the whole Howl.
+	Sprite string
+	// Weight is the relative chance of the entry being picked by
+	// PickWeighted. Zero counts as 1, and entries with a negative weight are
+	// never picked.
+	Weight float64
+}
+
+// SpriteEntries returns a container entry for each of the named sprites of h.
+func SpriteEntries(h Howl, names ...string) []ContainerEntry {
+	entries := make([]ContainerEntry, len(names))
+	for i, name := range names {
+		entries[i] = ContainerEntry{Howl: h, Sprite: name}
+	}
+	return entries
+}
+
+// Range is a range of values to pick from at random. The zero Range leaves the
+// value unchanged.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// IsZero returns true if r leaves the value unchanged.
+func (r Range) IsZero() bool {
+	return r == Range{}
+}
+
+// pick returns a value between Min and Max.
+func (r Range) pick(rng *rand.Rand) float64 {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rng.Float64()*(r.Max-r.Min)
+}
+
+// ContainerOptions configures a SoundContainer.
+type ContainerOptions struct {
+	// Pick sets how the entry to play is chosen.
+	Pick PickMode
+
+	// Volume is the range each sound's volume is picked from.
+	Volume Range
+
+	// Rate is the range each sound's playback rate is picked from.
+	Rate Range
+
+	// Rand is the source entries, volumes and rates are picked with. It must
+	// not be used by anything else while the container is. If nil, a source
+	// seeded from the current time is used.
+	Rand *rand.Rand
+}
+
+// SoundContainer plays one of several sounds each time it is played, such as
+// a set of footsteps or impacts, varying the volume and rate of each so that
+// repeated plays don't sound the same.
+type SoundContainer struct {
+	entries []ContainerEntry
+	opts    ContainerOptions
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	// order is the shuffled order of the entries for PickShuffle.
+	order []int
+	pos   int
+	// last is the index of the entry played last, or -1 if none has been.
+	last int
+}
+
+// NewSoundContainer creates a container playing entries.
+func NewSoundContainer(entries []ContainerEntry, opts ContainerOptions) *SoundContainer {
+	return &SoundContainer{
+		entries: append([]ContainerEntry(nil), entries...),
+		opts:    opts,
+		rand:    randOrNew(opts.Rand),
+		last:    -1,
+	}
+}
+
+// Entries returns the entries the container plays.
+func (c *SoundContainer) Entries() []ContainerEntry {
+	return append([]ContainerEntry(nil), c.entries...)
+}
+
+// Play picks an entry and plays it, returning the new sound with its volume
+// and rate set from the container's ranges. If the sound couldn't be played
+// the returned Sound is invalid.
+func (c *SoundContainer) Play() (Sound, error) {
+	c.mu.Lock()
+	index, ok := c.next()
+	volume, rate := c.opts.Volume.pick(c.rand), c.opts.Rate.pick(c.rand)
+	c.mu.Unlock()
+	if !ok {
+		return invalid(fmt.Errorf("%w: sound container has no entries to pick", ErrNotPlayed))
+	}
+
+	entry := c.entries[index]
+	var sound Sound
+	var err error
+	if entry.Sprite != "" {
+		sound, err = entry.Howl.PlaySprite(entry.Sprite)
+	} else {
+		sound, err = entry.Howl.TryPlay()
+	}
+	if err != nil {
+		return sound, err
+	}
+	if !c.opts.Volume.IsZero() {
+		sound.SetVolume(volume)
+	}
+	if !c.opts.Rate.IsZero() {
+		sound.SetRate(rate)
+	}
+	return sound, nil
+}
+
+// next returns the index of the entry to play, or false if there is none. The
+// caller must hold c.mu.
+func (c *SoundContainer) next() (int, bool) {
+	n := len(c.entries)
+	if n == 0 {
+		return 0, false
+	}
+
+	var index int
+	switch c.opts.Pick {
+	case PickShuffle:
+		if c.pos >= len(c.order) {
+			c.order = make([]int, n)
+			for i := range c.order {
+				c.order[i] = i
+			}
+			shuffle(c.rand, c.order)
+			// Don't play the last entry twice in a row.
+			if n > 1 && c.order[0] == c.last {
+				c.order[0], c.order[n-1] = c.order[n-1], c.order[0]
+			}
+			c.pos = 0
+		}
+		index = c.order[c.pos]
+		c.pos++
+	case PickSequential:
+		index = c.pos % n
+		c.pos = index + 1
+	case PickWeighted:
+		var total float64
+		for _, entry := range c.entries {
+			total += weightOf(entry)
+		}
+		if total == 0 {
+			return 0, false
+		}
+		r := c.rand.Float64() * total
+		index = -1
+		for i, entry := range c.entries {
+			w := weightOf(entry)
+			if w == 0 {
+				continue
+			}
+			index = i
+			if r < w {
+				break
+			}
+			r -= w
+		}
+	default:
+		index = c.rand.Intn(n)
+	}
+	c.last = index
+	return index, true
+}
+
+// weightOf returns the weight PickWeighted gives entry.
+func weightOf(entry ContainerEntry) float64 {
+	switch {
+	case entry.Weight < 0:
+		return 0
+	case entry.Weight == 0:
+		return 1
+	default:
+		return entry.Weight
+	}
+}
@@ -0,0 +1,186 @@
+package howler
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// containerPicks plays c n times, returning the index of the entry each play
+// came from. Each entry must be a different Howl.
+func containerPicks(t *testing.T, b *FakeBackend, c *SoundContainer, n int) []int {
+	t.Helper()
+	index := make(map[*soundStates]int)
+	for i, e := range c.Entries() {
+		index[e.Howl.states] = i
+	}
+	var picks []int
+	for i := 0; i < n; i++ {
+		sound, err := c.Play()
+		if err != nil {
+			t.Fatal(err)
+		}
+		picks = append(picks, index[sound.(soundSpecific).states])
+		b.Flush()
+	}
+	return picks
+}
+
+func TestSoundContainerPick(t *testing.T) {
+	tests := []struct {
+		name    string
+		pick    PickMode
+		weights []float64
+		check   func(t *testing.T, picks []int)
+	}{
+		{
+			name: "sequential",
+			pick: PickSequential,
+			check: func(t *testing.T, picks []int) {
+				for i, p := range picks {
+					if p != i%3 {
+						t.Fatalf("picks = %v, want the entries in order", picks)
+					}
+				}
+			},
+		},
+		{
+			name: "shuffle",
+			pick: PickShuffle,
+			check: func(t *testing.T, picks []int) {
+				for start := 0; start+3 <= len(picks); start += 3 {
+					seen := map[int]bool{}
+					for _, p := range picks[start : start+3] {
+						seen[p] = true
+					}
+					if len(seen) != 3 {
+						t.Fatalf("picks = %v, want every entry once in each round", picks)
+					}
+				}
+				for i := 1; i < len(picks); i++ {
+					if picks[i] == picks[i-1] {
+						t.Fatalf("picks = %v, want no entry twice in a row", picks)
+					}
+				}
+			},
+		},
+		{
+			name:    "weighted",
+			pick:    PickWeighted,
+			weights: []float64{-1, 0, 3},
+			check: func(t *testing.T, picks []int) {
+				counts := make([]int, 3)
+				for _, p := range picks {
+					counts[p]++
+				}
+				if counts[0] != 0 || counts[1] == 0 || counts[2] < 2*counts[1] {
+					t.Fatalf("counts = %v, want none of the first and about three times as many of the last as the second", counts)
+				}
+			},
+		},
+		{
+			name: "random",
+			pick: PickRandom,
+			check: func(t *testing.T, picks []int) {
+				seen := map[int]bool{}
+				for _, p := range picks {
+					seen[p] = true
+				}
+				if len(seen) != 3 {
+					t.Fatalf("picks = %v, want every entry picked at some point", picks)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			var entries []ContainerEntry
+			for i, src := range []string{"a.mp3", "b.mp3", "c.mp3"} {
+				entry := ContainerEntry{Howl: New(HowlOptions{Source: []string{src}})}
+				if i < len(tt.weights) {
+					entry.Weight = tt.weights[i]
+				}
+				entries = append(entries, entry)
+			}
+			newContainer := func() *SoundContainer {
+				return NewSoundContainer(entries, ContainerOptions{
+					Pick: tt.pick,
+					Rand: rand.New(rand.NewSource(7)),
+				})
+			}
+
+			picks := containerPicks(t, b, newContainer(), 60)
+			tt.check(t, picks)
+			if again := containerPicks(t, b, newContainer(), 60); !equalInts(picks, again) {
+				t.Errorf("containers with the same seed picked %v and %v", picks, again)
+			}
+		})
+	}
+}
+
+func TestSpriteEntries(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("steps.mp3", 2*time.Second)
+	h := New(HowlOptions{
+		Source: []string{"steps.mp3"},
+		Sprites: map[string]Sprite{
+			"left":  {Offset: 0, Duration: time.Second},
+			"right": {Offset: time.Second, Duration: time.Second},
+		},
+	})
+	c := NewSoundContainer(SpriteEntries(h, "left", "right"), ContainerOptions{Pick: PickSequential})
+	for _, want := range []time.Duration{0, time.Second} {
+		s, err := c.Play()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Flush()
+		if got := s.Seek(); got != want {
+			t.Errorf("sprite starts at %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSoundContainerRanges(t *testing.T) {
+	b := newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	c := NewSoundContainer([]ContainerEntry{{Howl: h}}, ContainerOptions{
+		Volume: Range{Min: 0.5, Max: 0.7},
+		Rate:   Range{Min: 0.9, Max: 1.1},
+	})
+	for i := 0; i < 20; i++ {
+		s, err := c.Play()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Flush()
+		if v := s.Volume(); v < 0.5 || v > 0.7 {
+			t.Errorf("Volume() = %v, want between 0.5 and 0.7", v)
+		}
+		if r := s.Rate(); r < 0.9 || r > 1.1 {
+			t.Errorf("Rate() = %v, want between 0.9 and 1.1", r)
+		}
+	}
+}
+
+func TestSoundContainerEmpty(t *testing.T) {
+	newTestBackend(t)
+	tests := []struct {
+		name    string
+		entries []ContainerEntry
+		pick    PickMode
+	}{
+		{"no entries", nil, PickRandom},
+		{"no weight", []ContainerEntry{{Weight: -1}}, PickWeighted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewSoundContainer(tt.entries, ContainerOptions{Pick: tt.pick})
+			if s, err := c.Play(); !errors.Is(err, ErrNotPlayed) || s.Valid() {
+				t.Errorf("Play() = %v, %v; want an invalid sound and %v", s, err, ErrNotPlayed)
+			}
+		})
+	}
+}
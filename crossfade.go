@@ -0,0 +1,180 @@
+package howler
+
+import (
+	"sync"
+	"time"
+)
+
+// fader is implemented by the package's own Sounds, which can run a function
+// as soon as a fade finishes.
+type fader interface {
+	fadeThen(from, to float64, duration time.Duration, curve FadeCurve, then func()) <-chan struct{}
+}
+
+// fadeThen fades sound, calling then once the fade finishes or is interrupted.
+func fadeThen(sound Sound, from, to float64, d time.Duration, curve FadeCurve, then func()) {
+	if f, ok := sound.(fader); ok {
+		f.fadeThen(from, to, d, curve, then)
+		return
+	}
+	done := sound.FadeWith(from, to, d, curve)
+	go func() {
+		<-done
+		then()
+	}()
+}
+
+// soundKey identifies a sound of one of the package's Howls, or the whole Howl
+// if id is -1. Howls are told apart by their Go side state, as Values can't be
+// compared under js/wasm.
+type soundKey struct {
+	states *soundStates
+	id     int
+}
+
+// keyOf returns the key identifying sound. Sounds implemented outside the
+// package are their own key, so they must be comparable.
+func keyOf(sound Sound) any {
+	switch s := sound.(type) {
+	case Howl:
+		return soundKey{s.states, -1}
+	case soundGroup:
+		return soundKey{s.states, -1}
+	case soundSpecific:
+		return soundKey{s.states, s.id}
+	default:
+		return sound
+	}
+}
+
+// crossfade is a crossfade in progress.
+type crossfade struct {
+	// rest holds the volume each sound settles at: the volume the incoming
+	// sound fades in to, and the volume the outgoing sound is restored to once
+	// stopped.
+	rest map[any]float64
+	// pending counts the fades still running.
+	pending int
+	done    chan struct{}
+	// then is called once done has been closed.
+	then func()
+}
+
+var crossfades = struct {
+	mu sync.Mutex
+	// owners maps each sound taking part in a crossfade to the crossfade.
+	owners map[any]*crossfade
+}{owners: make(map[any]*crossfade)}
+
+// Crossfade fades from one sound to another over d. The incoming sound is
+// played if it isn't playing already and fades in to its current volume,
+// while the outgoing sound fades out and is then stopped and restored to the
+// volume it had. Either may be nil to only fade in or out, and either may be a
+// Howl to fade every sound it is playing.
+//
+// The outgoing sound follows curve backwards, so passing FadeEqualPower keeps
+// the overall loudness steady. A nil curve gives howler.js's linear fades.
+//
+// Starting a crossfade involving a sound that is still part of an earlier one
+// takes it over: the sound fades on from the level it has reached towards the
+// volume the earlier crossfade would have left it at, and the earlier
+// crossfade no longer stops it.
+//
+// The returned channel is closed once both fades have finished or been
+// interrupted.
+func Crossfade(from, to Sound, d time.Duration, curve FadeCurve) <-chan struct{} {
+	_, done := startCrossfade(from, to, d, curve, nil)
+	return done
+}
+
+// startCrossfade starts a crossfade, returning the sound it played, if any,
+// and a channel that is closed once it has finished. If then isn't nil it is
+// called once the channel is closed.
+func startCrossfade(from, to Sound, d time.Duration, curve FadeCurve, then func()) (Sound, <-chan struct{}) {
+	if from != nil && to != nil && keyOf(from) == keyOf(to) {
+		return nil, finished(then)
+	}
+
+	c := &crossfade{
+		rest: make(map[any]float64),
+		done: make(chan struct{}),
+		then: then,
+	}
+
+	crossfades.mu.Lock()
+	for _, sound := range []Sound{from, to} {
+		if sound == nil {
+			continue
+		}
+		key := keyOf(sound)
+		if owner, ok := crossfades.owners[key]; ok {
+			c.rest[key] = owner.rest[key]
+		} else {
+			c.rest[key] = sound.Volume()
+		}
+		crossfades.owners[key] = c
+		c.pending++
+	}
+	crossfades.mu.Unlock()
+
+	if c.pending == 0 {
+		close(c.done)
+		return nil, finished(then)
+	}
+
+	var played Sound
+	if to != nil {
+		start := 0.0
+		if to.Playing() {
+			start = to.Volume()
+		} else {
+			to.SetVolume(0)
+			played = to.Play()
+		}
+		fadeThen(to, start, c.rest[keyOf(to)], d, curve, func() {
+			if _, last := c.release(to); last {
+				c.finish()
+			}
+		})
+	}
+
+	if from != nil {
+		fadeThen(from, from.Volume(), 0, d, curve, func() {
+			// Only stop the sound if the fade reached the end rather than
+			// being interrupted, and no later crossfade has taken it over.
+			owned, last := c.release(from)
+			if owned && from.Volume() == 0 {
+				from.Stop()
+				from.SetVolume(c.rest[keyOf(from)])
+			}
+			if last {
+				c.finish()
+			}
+		})
+	}
+
+	return played, c.done
+}
+
+// release records that the fade of sound has finished. It returns whether the
+// crossfade still owned the sound, and whether it was the last fade to finish.
+func (c *crossfade) release(sound Sound) (owned, last bool) {
+	crossfades.mu.Lock()
+	defer crossfades.mu.Unlock()
+
+	key := keyOf(sound)
+	owned = crossfades.owners[key] == c
+	if owned {
+		delete(crossfades.owners, key)
+	}
+	c.pending--
+	return owned, c.pending == 0
+}
+
+// finish closes the crossfade's channel once both of its fades have finished.
+func (c *crossfade) finish() {
+	close(c.done)
+	if c.then != nil {
+		c.then()
+	}
+}
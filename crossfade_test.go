@@ -0,0 +1,93 @@
+package howler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestKeyOf(t *testing.T) {
+	b := newTestBackend(t)
+	h1 := New(HowlOptions{Source: []string{"a.mp3"}})
+	h2 := New(HowlOptions{Source: []string{"a.mp3"}})
+	s1, s2 := h1.Play(), h1.Play()
+	b.Flush()
+
+	tests := []struct {
+		name string
+		a, b Sound
+		same bool
+	}{
+		{"same howl", h1, h1, true},
+		{"copied howl", h1, Howl{h1.soundGroup, h1.howlState}, true},
+		{"group of howl", h1, h1.soundGroup, true},
+		{"different howls", h1, h2, false},
+		{"same sound", s1, soundSpecific{id: s1.ID(), value: h1.value, states: h1.states}, true},
+		{"different sounds", s1, s2, false},
+		{"sound and howl", s1, h1, false},
+		{"same id in different howls", s1, soundSpecific{id: s1.ID(), value: h2.value, states: h2.states}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyOf(tt.a) == keyOf(tt.b); got != tt.same {
+				t.Errorf("keys equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestCrossfade(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	b.SetDuration("b.mp3", time.Minute)
+	a := New(HowlOptions{Source: []string{"a.mp3"}})
+	next := New(HowlOptions{Source: []string{"b.mp3"}, Volume: Some(0.8)})
+	a.Play()
+	b.Flush()
+
+	done := Crossfade(a, next, time.Second, FadeEqualPower)
+	b.Advance(500 * time.Millisecond)
+	if got, want := next.Volume(), 0.8*math.Sqrt2/2; math.Abs(got-want) > 0.02 {
+		t.Errorf("incoming volume halfway = %v, want %v", got, want)
+	}
+	b.Advance(500 * time.Millisecond)
+	if !isClosed(done) {
+		t.Fatal("crossfade didn't finish")
+	}
+	if a.Playing() || a.Volume() != 1 {
+		t.Errorf("outgoing playing, volume = %v, %v; want false, 1", a.Playing(), a.Volume())
+	}
+	if !next.Playing() || next.Volume() != 0.8 {
+		t.Errorf("incoming playing, volume = %v, %v; want true, 0.8", next.Playing(), next.Volume())
+	}
+
+	if !isClosed(Crossfade(next, next, time.Second, nil)) {
+		t.Error("crossfade from a sound to itself didn't finish straight away")
+	}
+}
+
+func TestCrossfadeTakeover(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	a := New(HowlOptions{Source: []string{"a.mp3"}})
+	c := New(HowlOptions{Source: []string{"a.mp3"}, Volume: Some(0.6)})
+	a.Play()
+	b.Flush()
+
+	first := Crossfade(a, c, time.Second, nil)
+	b.Advance(500 * time.Millisecond)
+	second := Crossfade(c, a, time.Second, nil)
+	b.Advance(time.Second)
+	b.Flush()
+	if !isClosed(first) || !isClosed(second) {
+		t.Fatalf("crossfades closed = %v, %v; want both", isClosed(first), isClosed(second))
+	}
+	// a fades back in to the volume it had before the first crossfade, and
+	// the first crossfade no longer stops it.
+	if !a.Playing() || a.Volume() != 1 {
+		t.Errorf("a playing, volume = %v, %v; want true, 1", a.Playing(), a.Volume())
+	}
+	if c.Playing() || c.Volume() != 0.6 {
+		t.Errorf("c playing, volume = %v, %v; want false, 0.6", c.Playing(), c.Volume())
+	}
+}
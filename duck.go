@@ -0,0 +1,298 @@
+package howler
+
+import (
+	"sync"
+	"time"
+)
+
+// DuckerOptions configures a Ducker.
+type DuckerOptions struct {
+	// Amount is how far the targets are ducked, as a fraction of their volume:
+	// 0.5 halves it and 1 silences them. It is clamped between 0 and 1.
+	Amount float64
+
+	// Attack is how long the targets take to fade down once a trigger starts
+	// playing.
+	Attack time.Duration
+
+	// Release is how long the targets take to fade back up once every trigger
+	// has stopped playing.
+	Release time.Duration
+
+	// Curve is the curve the fades follow. A nil curve gives howler.js's
+	// linear fades.
+	Curve FadeCurve
+}
+
+// Ducker lowers the volume of a set of target sounds while any sound of a set
+// of trigger Howls is playing, such as ducking the music under dialogue.
+// Triggers overlapping each other keep the targets ducked until the last one
+// stops, pauses or ends.
+//
+// The volume a target is restored to is the volume it had when it was first
+// ducked. Setting a target's volume while it is ducked is undone once the
+// ducking is released.
+type Ducker struct {
+	opts DuckerOptions
+
+	mu       sync.Mutex
+	triggers map[*soundStates]duckTrigger
+	targets  map[any]Sound
+	// playing holds the trigger sounds that are playing.
+	playing map[soundKey]Sound
+	ducked  bool
+	// rest holds the volume each target is restored to, for targets that are
+	// ducked or still fading back up.
+	rest map[any]float64
+	// gen is increased whenever the ducking starts or is released, so fades
+	// finishing late can tell they have been superseded.
+	gen int
+}
+
+// duckTrigger is a trigger Howl and the listeners added to it.
+type duckTrigger struct {
+	howl Howl
+	subs []Subscription
+}
+
+// NewDucker creates a Ducker lowering the volume of targets while triggers are
+// playing.
+func NewDucker(triggers []Howl, targets []Sound, opts DuckerOptions) *Ducker {
+	if opts.Amount < 0 {
+		opts.Amount = 0
+	} else if opts.Amount > 1 {
+		opts.Amount = 1
+	}
+	d := &Ducker{
+		opts:     opts,
+		triggers: make(map[*soundStates]duckTrigger),
+		targets:  make(map[any]Sound),
+		playing:  make(map[soundKey]Sound),
+		rest:     make(map[any]float64),
+	}
+	for _, target := range targets {
+		d.AddTarget(target)
+	}
+	for _, trigger := range triggers {
+		d.AddTrigger(trigger)
+	}
+	return d
+}
+
+// AddTrigger starts watching h, ducking the targets while any of its sounds is
+// playing.
+func (d *Ducker) AddTrigger(h Howl) {
+	d.mu.Lock()
+	if _, ok := d.triggers[h.states]; ok {
+		d.mu.Unlock()
+		return
+	}
+
+	// The listeners are only called once the events they hear have been
+	// queued and run, so they can't be called while d.mu is held.
+	trigger := duckTrigger{howl: h}
+	for _, event := range []Event{EventPlay, EventEnd, EventPause, EventStop} {
+		trigger.subs = append(trigger.subs, h.On(event, d.changed))
+	}
+	d.triggers[h.states] = trigger
+	for _, s := range h.Sounds() {
+		if s.Playing() {
+			d.playing[soundKey{h.states, s.ID()}] = s
+		}
+	}
+	fades := d.update()
+	d.mu.Unlock()
+
+	fades.run()
+}
+
+// RemoveTrigger stops watching h, releasing the targets if none of the other
+// triggers is playing.
+func (d *Ducker) RemoveTrigger(h Howl) {
+	d.mu.Lock()
+	trigger, ok := d.triggers[h.states]
+	delete(d.triggers, h.states)
+	for k := range d.playing {
+		if k.states == h.states {
+			delete(d.playing, k)
+		}
+	}
+	fades := d.update()
+	d.mu.Unlock()
+
+	if ok {
+		for _, sub := range trigger.subs {
+			h.Off(sub)
+		}
+	}
+	fades.run()
+}
+
+// AddTarget adds a sound to duck, which may be a Howl to duck every sound it
+// plays. It is ducked straight away if a trigger is playing.
+func (d *Ducker) AddTarget(target Sound) {
+	d.mu.Lock()
+	key := keyOf(target)
+	var fades duckFades
+	if _, ok := d.targets[key]; !ok {
+		d.targets[key] = target
+		if d.ducked {
+			fades = append(fades, d.duck(key, target))
+		}
+	}
+	d.mu.Unlock()
+
+	fades.run()
+}
+
+// RemoveTarget stops ducking target, restoring its volume straight away if it
+// was ducked.
+func (d *Ducker) RemoveTarget(target Sound) {
+	d.mu.Lock()
+	key := keyOf(target)
+	_, ok := d.targets[key]
+	rest, ducked := d.rest[key]
+	delete(d.targets, key)
+	delete(d.rest, key)
+	d.mu.Unlock()
+
+	if ok && ducked {
+		target.SetVolume(rest)
+	}
+}
+
+// Ducked returns true while the targets are ducked.
+func (d *Ducker) Ducked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ducked
+}
+
+// Close stops watching every trigger and restores the targets to their
+// volumes straight away.
+func (d *Ducker) Close() {
+	d.mu.Lock()
+	triggers := d.triggers
+	targets := d.targets
+	rest := d.rest
+	d.triggers = make(map[*soundStates]duckTrigger)
+	d.targets = make(map[any]Sound)
+	d.playing = make(map[soundKey]Sound)
+	d.rest = make(map[any]float64)
+	d.ducked = false
+	d.gen++
+	d.mu.Unlock()
+
+	for _, trigger := range triggers {
+		for _, sub := range trigger.subs {
+			trigger.howl.Off(sub)
+		}
+	}
+	for key, volume := range rest {
+		targets[key].SetVolume(volume)
+	}
+}
+
+// changed handles a trigger sound starting or stopping.
+func (d *Ducker) changed(s Sound) {
+	// Trigger listeners are only given the package's own Sounds.
+	key := keyOf(s).(soundKey)
+	d.mu.Lock()
+	if s.Playing() {
+		d.playing[key] = s
+	} else {
+		delete(d.playing, key)
+	}
+	fades := d.update()
+	d.mu.Unlock()
+
+	fades.run()
+}
+
+// duckFade is a fade of a target to start once d.mu has been released.
+type duckFade struct {
+	target   Sound
+	from, to float64
+	d        time.Duration
+	curve    FadeCurve
+	// then is called once the fade finishes, if it isn't nil.
+	then func()
+}
+
+type duckFades []duckFade
+
+func (fades duckFades) run() {
+	for _, f := range fades {
+		if f.then != nil {
+			fadeThen(f.target, f.from, f.to, f.d, f.curve, f.then)
+		} else {
+			f.target.FadeWith(f.from, f.to, f.d, f.curve)
+		}
+	}
+}
+
+// update ducks or releases the targets if the triggers have started or
+// stopped playing, returning the fades to run. The caller must hold d.mu.
+func (d *Ducker) update() duckFades {
+	// Sounds can stop without an event, such as when their Howl is unloaded.
+	for key, s := range d.playing {
+		if !s.Playing() {
+			delete(d.playing, key)
+		}
+	}
+
+	ducked := len(d.playing) > 0
+	if ducked == d.ducked {
+		return nil
+	}
+	d.ducked = ducked
+	d.gen++
+
+	fades := make(duckFades, 0, len(d.targets))
+	for key, target := range d.targets {
+		if ducked {
+			fades = append(fades, d.duck(key, target))
+		} else {
+			fades = append(fades, d.release(key, target))
+		}
+	}
+	return fades
+}
+
+// duck returns the fade ducking target. The caller must hold d.mu.
+func (d *Ducker) duck(key any, target Sound) duckFade {
+	rest, ok := d.rest[key]
+	if !ok {
+		rest = target.Volume()
+		d.rest[key] = rest
+	}
+	return duckFade{
+		target: target,
+		from:   target.Volume(),
+		to:     rest * (1 - d.opts.Amount),
+		d:      d.opts.Attack,
+		curve:  d.opts.Curve,
+	}
+}
+
+// release returns the fade restoring target to its volume. The caller must
+// hold d.mu.
+func (d *Ducker) release(key any, target Sound) duckFade {
+	gen := d.gen
+	return duckFade{
+		target: target,
+		from:   target.Volume(),
+		to:     d.rest[key],
+		d:      d.opts.Release,
+		curve:  d.opts.Curve,
+		then: func() {
+			// Once back at its volume the target no longer needs restoring,
+			// so later changes to its volume are kept.
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			if d.gen == gen {
+				delete(d.rest, key)
+			}
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package howler
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDucker(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		curve  FadeCurve
+		want   float64
+	}{
+		{"half", 0.5, nil, 0.4},
+		{"silence", 1, FadeEqualPower, 0},
+		{"clamped", 2, nil, 0},
+		{"none", -1, nil, 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.SetDuration("music.mp3", time.Minute)
+			b.SetDuration("voice.mp3", time.Second)
+			music := New(HowlOptions{Source: []string{"music.mp3"}, Volume: Some(0.8)})
+			voice := New(HowlOptions{Source: []string{"voice.mp3"}})
+			music.Play()
+			b.Flush()
+
+			d := NewDucker([]Howl{voice}, []Sound{music}, DuckerOptions{
+				Amount:  tt.amount,
+				Attack:  100 * time.Millisecond,
+				Release: 200 * time.Millisecond,
+				Curve:   tt.curve,
+			})
+			defer d.Close()
+
+			voice.Play()
+			b.Advance(100 * time.Millisecond)
+			if !d.Ducked() {
+				t.Fatal("not ducked while the trigger plays")
+			}
+			if got := music.Volume(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ducked volume = %v, want %v", got, tt.want)
+			}
+
+			// The voice ends after a second, then the music fades back up.
+			b.Advance(900 * time.Millisecond)
+			if d.Ducked() {
+				t.Error("still ducked after the trigger ended")
+			}
+			b.Advance(200 * time.Millisecond)
+			if got := music.Volume(); math.Abs(got-0.8) > 1e-9 {
+				t.Errorf("released volume = %v, want 0.8", got)
+			}
+		})
+	}
+}
+
+func TestDuckerOverlappingTriggers(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Second)
+	b.SetDuration("b.mp3", 2*time.Second)
+	music := New(HowlOptions{Source: []string{"music.mp3"}})
+	a := New(HowlOptions{Source: []string{"a.mp3"}})
+	c := New(HowlOptions{Source: []string{"b.mp3"}})
+	d := NewDucker([]Howl{a, c}, []Sound{music}, DuckerOptions{Amount: 0.5})
+	defer d.Close()
+
+	a.Play()
+	c.Play()
+	b.Advance(time.Second + time.Millisecond)
+	if !d.Ducked() {
+		t.Error("released while a trigger still plays")
+	}
+	b.Advance(time.Second)
+	if d.Ducked() {
+		t.Error("still ducked after both triggers ended")
+	}
+
+	// Removing a playing trigger releases the targets.
+	c.Play()
+	b.Flush()
+	d.RemoveTrigger(c)
+	if d.Ducked() {
+		t.Error("still ducked after the playing trigger was removed")
+	}
+}
+
+func TestDuckerTargets(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("voice.mp3", time.Minute)
+	music := New(HowlOptions{Source: []string{"music.mp3"}, Volume: Some(0.6)})
+	voice := New(HowlOptions{Source: []string{"voice.mp3"}})
+	d := NewDucker([]Howl{voice}, nil, DuckerOptions{Amount: 0.5})
+
+	voice.Play()
+	b.Flush()
+	d.AddTarget(music)
+	b.Flush()
+	if got := music.Volume(); math.Abs(got-0.3) > 1e-9 {
+		t.Errorf("volume of target added while ducked = %v, want 0.3", got)
+	}
+	d.RemoveTarget(music)
+	if got := music.Volume(); got != 0.6 {
+		t.Errorf("volume of removed target = %v, want 0.6", got)
+	}
+
+	d.AddTarget(music)
+	b.Flush()
+	d.Close()
+	if got := music.Volume(); got != 0.6 {
+		t.Errorf("volume after Close = %v, want 0.6", got)
+	}
+}
+
+func TestDuckerAddTriggerConcurrent(t *testing.T) {
+	newTestBackend(t)
+	voice := New(HowlOptions{Source: []string{"voice.mp3"}})
+	d := NewDucker(nil, nil, DuckerOptions{})
+	defer d.Close()
+	listeners := func() int {
+		voice.funcs.mu.Lock()
+		defer voice.funcs.mu.Unlock()
+		return len(voice.funcs.funcs)
+	}
+	before := listeners()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.AddTrigger(voice)
+		}()
+	}
+	wg.Wait()
+
+	if n := listeners() - before; n != 4 {
+		t.Errorf("%d listeners added to the trigger, want 4", n)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.triggers) != 1 {
+		t.Errorf("%d triggers, want 1", len(d.triggers))
+	}
+}
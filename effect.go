@@ -0,0 +1,407 @@
+package howler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// newNode creates a node by calling one of the AudioContext's factory
+// methods, failing with ErrNoWebAudio if there is no context.
+func newNode(method string, args ...any) (ctx Value, node Value, err error) {
+	ctx = AudioContext()
+	if ctx == nil {
+		return nil, nil, ErrNoWebAudio
+	}
+	return ctx, ctx.Call(method, args...), nil
+}
+
+// param returns the current value of one of node's AudioParams.
+func param(node Value, name string) float64 {
+	return node.Get(name).Get("value").Float()
+}
+
+// setParam sets one of node's AudioParams straight away, the way howler.js
+// sets its gains.
+func setParam(ctx, node Value, name string, value float64) {
+	node.Get(name).Call("setValueAtTime", value, ctx.Get("currentTime").Float())
+}
+
+// setOptionalParam sets one of node's AudioParams if o has been set.
+func setOptionalParam(ctx, node Value, name string, o Optional[float64]) {
+	if v, ok := o.Get(); ok {
+		setParam(ctx, node, name, v)
+	}
+}
+
+// FilterType is the kind of filter a BiquadFilter applies.
+type FilterType string
+
+const (
+	FilterLowpass   FilterType = "lowpass"
+	FilterHighpass  FilterType = "highpass"
+	FilterBandpass  FilterType = "bandpass"
+	FilterLowshelf  FilterType = "lowshelf"
+	FilterHighshelf FilterType = "highshelf"
+	FilterPeaking   FilterType = "peaking"
+	FilterNotch     FilterType = "notch"
+	FilterAllpass   FilterType = "allpass"
+)
+
+// BiquadOptions configures a BiquadFilter. Unset values keep the Web Audio
+// defaults.
+type BiquadOptions struct {
+	Type FilterType // default=FilterLowpass
+	// Frequency is the cutoff or centre frequency in hertz.
+	Frequency Optional[float64] // default=350
+	Q         Optional[float64] // default=1
+	// Gain is the boost in decibels of the shelf and peaking filters.
+	Gain Optional[float64] // default=0
+}
+
+// BiquadFilter is an Effect filtering the signal with a BiquadFilterNode, such
+// as a low-pass filter for sounds heard underwater.
+type BiquadFilter struct {
+	ctx  Value
+	node Value
+}
+
+// NewBiquadFilter creates a filter in howler.js's AudioContext.
+func NewBiquadFilter(opts BiquadOptions) (*BiquadFilter, error) {
+	ctx, node, err := newNode("createBiquadFilter")
+	if err != nil {
+		return nil, err
+	}
+	f := &BiquadFilter{ctx: ctx, node: node}
+	if opts.Type != "" {
+		f.SetType(opts.Type)
+	}
+	setOptionalParam(ctx, node, "frequency", opts.Frequency)
+	setOptionalParam(ctx, node, "Q", opts.Q)
+	setOptionalParam(ctx, node, "gain", opts.Gain)
+	return f, nil
+}
+
+func (f *BiquadFilter) Input() Value  { return f.node }
+func (f *BiquadFilter) Output() Value { return f.node }
+
+// Node returns the filter's BiquadFilterNode.
+func (f *BiquadFilter) Node() Value {
+	return f.node
+}
+
+func (f *BiquadFilter) Type() FilterType {
+	return FilterType(f.node.Get("type").String())
+}
+
+func (f *BiquadFilter) SetType(t FilterType) {
+	f.node.Set("type", string(t))
+}
+
+func (f *BiquadFilter) Frequency() float64 {
+	return param(f.node, "frequency")
+}
+
+func (f *BiquadFilter) SetFrequency(hz float64) {
+	setParam(f.ctx, f.node, "frequency", hz)
+}
+
+func (f *BiquadFilter) Q() float64 {
+	return param(f.node, "Q")
+}
+
+func (f *BiquadFilter) SetQ(q float64) {
+	setParam(f.ctx, f.node, "Q", q)
+}
+
+func (f *BiquadFilter) Gain() float64 {
+	return param(f.node, "gain")
+}
+
+func (f *BiquadFilter) SetGain(db float64) {
+	setParam(f.ctx, f.node, "gain", db)
+}
+
+// wetDry splits the signal between a dry path and a wet path through an
+// effect's nodes, mixing them back together at its output.
+type wetDry struct {
+	ctx    Value
+	input  Value
+	output Value
+	dry    Value
+	wet    Value
+}
+
+// newWetDry creates the gain nodes of a wetDry, without connecting the wet
+// path, mixing in the given share of the wet signal.
+func newWetDry(ctx Value, mix float64) wetDry {
+	w := wetDry{
+		ctx:    ctx,
+		input:  ctx.Call("createGain"),
+		output: ctx.Call("createGain"),
+		dry:    ctx.Call("createGain"),
+		wet:    ctx.Call("createGain"),
+	}
+	w.input.Call("connect", w.dry)
+	w.dry.Call("connect", w.output)
+	w.wet.Call("connect", w.output)
+	w.SetMix(mix)
+	return w
+}
+
+func (w wetDry) Input() Value  { return w.input }
+func (w wetDry) Output() Value { return w.output }
+
+// Mix returns the share of the output that has passed through the effect,
+// from 0 for none to 1 for all of it.
+func (w wetDry) Mix() float64 {
+	return param(w.wet, "gain")
+}
+
+// SetMix sets the share of the output that has passed through the effect.
+// Values out of range are ignored.
+func (w wetDry) SetMix(mix float64) {
+	if mix < 0 || mix > 1 {
+		return
+	}
+	setParam(w.ctx, w.dry, "gain", 1-mix)
+	setParam(w.ctx, w.wet, "gain", mix)
+}
+
+// ConvolverOptions configures a Convolver.
+type ConvolverOptions struct {
+	// Normalize scales the impulse response to an even level.
+	Normalize Optional[bool] // default=true
+	// Mix is the share of the output that is reverberated.
+	Mix Optional[float64] // default=0.5
+}
+
+// Convolver is an Effect convolving the signal with an impulse response
+// through a ConvolverNode, such as the reverb of a cave. It passes the signal
+// through unchanged until it is given an impulse response with LoadImpulse or
+// SetBuffer.
+type Convolver struct {
+	wetDry
+	node Value
+}
+
+// NewConvolver creates a convolver in howler.js's AudioContext.
+func NewConvolver(opts ConvolverOptions) (*Convolver, error) {
+	ctx, node, err := newNode("createConvolver")
+	if err != nil {
+		return nil, err
+	}
+	c := &Convolver{
+		wetDry: newWetDry(ctx, opts.Mix.Or(0.5)),
+		node:   node,
+	}
+	setOptional(node, "normalize", opts.Normalize)
+	c.input.Call("connect", node)
+	node.Call("connect", c.wet)
+	return c, nil
+}
+
+// Node returns the convolver's ConvolverNode.
+func (c *Convolver) Node() Value {
+	return c.node
+}
+
+// SetBuffer sets the impulse response to an AudioBuffer.
+func (c *Convolver) SetBuffer(buffer Value) {
+	c.node.Set("buffer", buffer)
+}
+
+// LoadImpulse waits for ir to load and uses its audio as the impulse
+// response, returning the *LoadError if it fails to load or ctx's error if ctx
+// is done first. It fails with ErrNoWebAudio if ir uses HTML5 Audio.
+//
+// howler.js keeps decoded audio to itself, so ir is played muted for an
+// instant to get at it. It should be a Howl kept only for the impulse
+// response. Like Wait, LoadImpulse must not be called from an event callback.
+func (c *Convolver) LoadImpulse(ctx context.Context, ir Howl) error {
+	if !webAudio(ir.value) {
+		return ErrNoWebAudio
+	}
+	if ir.State() == StateUnloaded {
+		ir.Load()
+	}
+	if err := ir.Wait(ctx); err != nil {
+		return err
+	}
+
+	result := ir.value.Call("play")
+	if !result.Truthy() {
+		return fmt.Errorf("%w: impulse response", ErrNotPlayed)
+	}
+	id := result.Int()
+	ir.value.Call("mute", true, id)
+	defer ir.value.Call("stop", id)
+
+	// The buffer is only handed over once the sound starts, which waits for
+	// the AudioContext to be unlocked.
+	if buffer := impulseBuffer(ir.value, id); buffer != nil {
+		c.SetBuffer(buffer)
+		return nil
+	}
+	played := make(chan struct{})
+	sub := ir.listen("once", EventPlay, id, func(Sound) {
+		close(played)
+	})
+	select {
+	case <-played:
+	case <-ctx.Done():
+		ir.Off(sub)
+		return ctx.Err()
+	}
+	if buffer := impulseBuffer(ir.value, id); buffer != nil {
+		c.SetBuffer(buffer)
+		return nil
+	}
+	return fmt.Errorf("%w: impulse response", ErrNotPlayed)
+}
+
+// impulseBuffer returns the AudioBuffer sound id of howl is playing, or nil if
+// it hasn't started.
+func impulseBuffer(howl Value, id int) Value {
+	node := soundNode(howl, id)
+	if node == nil {
+		return nil
+	}
+	source := node.Get("bufferSource")
+	if !source.Truthy() {
+		return nil
+	}
+	if buffer := source.Get("buffer"); buffer.Truthy() {
+		return buffer
+	}
+	return nil
+}
+
+// DelayOptions configures a Delay.
+type DelayOptions struct {
+	// Time is how far the echoes are delayed.
+	Time time.Duration
+	// MaxTime is the longest Time the delay can be changed to.
+	MaxTime time.Duration // default=Time or one second, whichever is longer
+	// Feedback is how much of each echo is fed back to be delayed again, from 0
+	// for a single echo to just below 1 for echoes that take a long time to
+	// die away.
+	Feedback float64
+	// Mix is the share of the output that is echoed.
+	Mix Optional[float64] // default=0.5
+}
+
+// Delay is an Effect adding echoes of the signal through a DelayNode.
+type Delay struct {
+	wetDry
+	node     Value
+	feedback Value
+}
+
+// NewDelay creates a delay in howler.js's AudioContext.
+func NewDelay(opts DelayOptions) (*Delay, error) {
+	max := opts.MaxTime
+	if max <= 0 {
+		max = time.Second
+		if opts.Time > max {
+			max = opts.Time
+		}
+	}
+	ctx, node, err := newNode("createDelay", max.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	d := &Delay{
+		wetDry:   newWetDry(ctx, opts.Mix.Or(0.5)),
+		node:     node,
+		feedback: ctx.Call("createGain"),
+	}
+	d.input.Call("connect", node)
+	node.Call("connect", d.wet)
+	node.Call("connect", d.feedback)
+	d.feedback.Call("connect", node)
+	d.SetTime(opts.Time)
+	d.SetFeedback(opts.Feedback)
+	return d, nil
+}
+
+// Node returns the delay's DelayNode.
+func (d *Delay) Node() Value {
+	return d.node
+}
+
+func (d *Delay) Time() time.Duration {
+	return time.Duration(param(d.node, "delayTime") * float64(time.Second))
+}
+
+// SetTime sets how far the echoes are delayed, up to the delay's MaxTime.
+func (d *Delay) SetTime(t time.Duration) {
+	setParam(d.ctx, d.node, "delayTime", t.Seconds())
+}
+
+func (d *Delay) Feedback() float64 {
+	return param(d.feedback, "gain")
+}
+
+// SetFeedback sets how much of each echo is delayed again. Values below 0 or
+// from 1 up, which would never die away, are ignored.
+func (d *Delay) SetFeedback(feedback float64) {
+	if feedback < 0 || feedback >= 1 {
+		return
+	}
+	setParam(d.ctx, d.feedback, "gain", feedback)
+}
+
+// CompressorOptions configures a Compressor. Unset values keep the Web Audio
+// defaults.
+type CompressorOptions struct {
+	// Threshold is the level in decibels above which the signal is compressed.
+	Threshold Optional[float64] // default=-24
+	// Knee is the range in decibels above the threshold over which the
+	// compression eases in.
+	Knee Optional[float64] // default=30
+	// Ratio is how many decibels the input must rise for the output to rise by
+	// one.
+	Ratio   Optional[float64]       // default=12
+	Attack  Optional[time.Duration] // default=3ms
+	Release Optional[time.Duration] // default=250ms
+}
+
+// Compressor is an Effect evening out the level of the signal through a
+// DynamicsCompressorNode.
+type Compressor struct {
+	ctx  Value
+	node Value
+}
+
+// NewCompressor creates a compressor in howler.js's AudioContext.
+func NewCompressor(opts CompressorOptions) (*Compressor, error) {
+	ctx, node, err := newNode("createDynamicsCompressor")
+	if err != nil {
+		return nil, err
+	}
+	setOptionalParam(ctx, node, "threshold", opts.Threshold)
+	setOptionalParam(ctx, node, "knee", opts.Knee)
+	setOptionalParam(ctx, node, "ratio", opts.Ratio)
+	if attack, ok := opts.Attack.Get(); ok {
+		setParam(ctx, node, "attack", attack.Seconds())
+	}
+	if release, ok := opts.Release.Get(); ok {
+		setParam(ctx, node, "release", release.Seconds())
+	}
+	return &Compressor{ctx: ctx, node: node}, nil
+}
+
+func (c *Compressor) Input() Value  { return c.node }
+func (c *Compressor) Output() Value { return c.node }
+
+// Node returns the compressor's DynamicsCompressorNode.
+func (c *Compressor) Node() Value {
+	return c.node
+}
+
+// Reduction returns how many decibels the signal is currently being reduced
+// by, as a negative number.
+func (c *Compressor) Reduction() float64 {
+	return c.node.Get("reduction").Float()
+}
@@ -0,0 +1,122 @@
+package howler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBiquadFilter(t *testing.T) {
+	newTestBackend(t)
+	f, err := NewBiquadFilter(BiquadOptions{Type: FilterHighpass, Frequency: Some(800.0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type() != FilterHighpass || f.Frequency() != 800 || f.Q() != 1 {
+		t.Errorf("type, frequency, Q = %v, %v, %v; want highpass, 800, 1", f.Type(), f.Frequency(), f.Q())
+	}
+	f.SetType(FilterPeaking)
+	f.SetQ(4)
+	f.SetGain(-6)
+	if f.Type() != FilterPeaking || f.Q() != 4 || f.Gain() != -6 {
+		t.Errorf("type, Q, gain = %v, %v, %v; want peaking, 4, -6", f.Type(), f.Q(), f.Gain())
+	}
+}
+
+func TestDelay(t *testing.T) {
+	newTestBackend(t)
+	d, err := NewDelay(DelayOptions{Time: 250 * time.Millisecond, Feedback: 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Time() != 250*time.Millisecond || d.Feedback() != 0.4 || d.Mix() != 0.5 {
+		t.Errorf("time, feedback, mix = %v, %v, %v; want 250ms, 0.4, 0.5", d.Time(), d.Feedback(), d.Mix())
+	}
+	if got := d.Node().Get("maxDelayTime").Float(); got != 1 {
+		t.Errorf("maxDelayTime = %v, want 1", got)
+	}
+
+	tests := []struct {
+		feedback, mix float64
+		wantFeedback  float64
+		wantMix       float64
+	}{
+		{0.8, 1, 0.8, 1},
+		{1, -0.5, 0.8, 1},
+		{-1, 2, 0.8, 1},
+		{0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		d.SetFeedback(tt.feedback)
+		d.SetMix(tt.mix)
+		if d.Feedback() != tt.wantFeedback || d.Mix() != tt.wantMix {
+			t.Errorf("after SetFeedback(%v), SetMix(%v): feedback, mix = %v, %v; want %v, %v",
+				tt.feedback, tt.mix, d.Feedback(), d.Mix(), tt.wantFeedback, tt.wantMix)
+		}
+	}
+}
+
+func TestCompressor(t *testing.T) {
+	newTestBackend(t)
+	c, err := NewCompressor(CompressorOptions{Ratio: Some(4.0), Attack: Some(10 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		param string
+		want  float64
+	}{
+		{"threshold", -24},
+		{"ratio", 4},
+		{"attack", 0.01},
+		{"release", 0.25},
+	}
+	for _, tt := range tests {
+		if got := param(c.Node(), tt.param); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestConvolverLoadImpulse(t *testing.T) {
+	b := newTestBackend(t)
+	c, err := NewConvolver(ConvolverOptions{Normalize: Some(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Node().Get("buffer").Truthy() {
+		t.Fatal("new convolver already has a buffer")
+	}
+	if c.Node().Get("normalize").Bool() {
+		t.Error("normalize wasn't turned off")
+	}
+
+	ir := New(HowlOptions{Source: []string{"cave.wav"}})
+	if err := c.LoadImpulse(context.Background(), ir); err != nil {
+		t.Fatal(err)
+	}
+	b.Flush()
+	if got := c.Node().Get("buffer").Get("src").String(); got != "cave.wav" {
+		t.Errorf("buffer is from %q, want cave.wav", got)
+	}
+	if ir.Playing() {
+		t.Error("impulse response is still playing")
+	}
+
+	html5 := New(HowlOptions{Source: []string{"cave.wav"}, HTML5: Some(true)})
+	if err := c.LoadImpulse(context.Background(), html5); !errors.Is(err, ErrNoWebAudio) {
+		t.Errorf("LoadImpulse of an HTML5 Howl = %v, want %v", err, ErrNoWebAudio)
+	}
+}
+
+func TestEffectsNoWebAudio(t *testing.T) {
+	b := newTestBackend(t)
+	b.howler.props["usingWebAudio"] = false
+	if AudioContext() != nil || MasterGain() != nil {
+		t.Error("AudioContext or MasterGain isn't nil without Web Audio")
+	}
+	if _, err := NewDelay(DelayOptions{}); !errors.Is(err, ErrNoWebAudio) {
+		t.Errorf("NewDelay() = %v, want %v", err, ErrNoWebAudio)
+	}
+}
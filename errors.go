@@ -0,0 +1,199 @@
+package howler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoAudio is reported when the browser has no audio support at all.
+	ErrNoAudio = errors.New("howler: no audio support")
+	// ErrUnsupportedCodec is reported when none of a Howl's sources are in a
+	// format the browser can play.
+	ErrUnsupportedCodec = errors.New("howler: no codec support for selected audio sources")
+	// ErrNetwork is reported when an audio file couldn't be downloaded.
+	ErrNetwork = errors.New("howler: network error loading audio")
+	// ErrDecode is reported when an audio file was downloaded but couldn't be
+	// decoded.
+	ErrDecode = errors.New("howler: decoding audio data failed")
+	// ErrAborted is reported when loading was aborted by the browser.
+	ErrAborted = errors.New("howler: loading audio aborted")
+	// ErrLocked is reported when playback couldn't start because audio hasn't
+	// been unlocked by a user interaction yet.
+	ErrLocked = errors.New("howler: audio is locked until user interaction")
+	// ErrUnknownSprite is reported when playing a sprite the Howl doesn't have.
+	ErrUnknownSprite = errors.New("howler: unknown sprite")
+	// ErrNotPlayed is reported when howler.js refused to play a sound without
+	// saying why, such as when the Howl has been unloaded.
+	ErrNotPlayed = errors.New("howler: sound didn't play")
+	// ErrVoiceLimit is reported when a sound isn't played because a voice
+	// limit has been reached and no playing sound could be stolen.
+	ErrVoiceLimit = errors.New("howler: voice limit reached")
+	// ErrNoWebAudio is reported when an effect is created or inserted without
+	// the Web Audio API, such as for a Howl using HTML5 Audio.
+	ErrNoWebAudio = errors.New("howler: Web Audio isn't in use")
+)
+
+// MediaErrorCode is a code from the HTML5 MediaError interface, which howler.js
+// reports when HTML5 Audio fails to load a file.
+type MediaErrorCode int
+
+const (
+	MediaErrorAborted         MediaErrorCode = 1
+	MediaErrorNetwork         MediaErrorCode = 2
+	MediaErrorDecode          MediaErrorCode = 3
+	MediaErrorSrcNotSupported MediaErrorCode = 4
+)
+
+// Err returns the sentinel error for the code, or nil if it is unknown.
+func (c MediaErrorCode) Err() error {
+	switch c {
+	case MediaErrorAborted:
+		return ErrAborted
+	case MediaErrorNetwork:
+		return ErrNetwork
+	case MediaErrorDecode:
+		return ErrDecode
+	case MediaErrorSrcNotSupported:
+		return ErrUnsupportedCodec
+	default:
+		return nil
+	}
+}
+
+// LoadError is passed to OnLoadError when a Howl fails to load. It wraps one
+// of the sentinel errors when the cause is known, so it can be checked with
+// errors.Is.
+type LoadError struct {
+	// Code is the MediaError code reported by HTML5 Audio, or zero if howler.js
+	// reported a message instead.
+	Code MediaErrorCode
+	// Status is the HTTP status of a failed Web Audio request, if known.
+	Status int
+	// SoundID is the sound that failed to load, or -1 if the failure wasn't
+	// tied to a single sound.
+	SoundID int
+	// Source is the source howler.js was loading, if it got as far as choosing
+	// one.
+	Source string
+	// Message is the message reported by howler.js.
+	Message string
+	// Err is the sentinel error for the cause, or nil if it is unknown.
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("howler: failed to load %s: %s", e.Source, e.Message)
+	}
+	return "howler: failed to load: " + e.Message
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// PlayError is passed to OnPlayError when a sound fails to play.
+type PlayError struct {
+	// SoundID is the sound that failed to play.
+	SoundID int
+	// Message is the message reported by howler.js.
+	Message string
+	// Err is the sentinel error for the cause, or nil if it is unknown.
+	Err error
+}
+
+func (e *PlayError) Error() string {
+	return fmt.Sprintf("howler: failed to play sound %d: %s", e.SoundID, e.Message)
+}
+
+func (e *PlayError) Unwrap() error {
+	return e.Err
+}
+
+var statusPattern = regexp.MustCompile(`status: (\d+)`)
+
+// eventError builds the error for a loaderror or playerror event fired by howl
+// with the given listener arguments.
+func eventError(event string, howl Value, args []Value) error {
+	if strings.HasSuffix(event, "loaderror") {
+		return newLoadError(howl, args)
+	}
+	return newPlayError(args)
+}
+
+func newLoadError(howl Value, args []Value) *LoadError {
+	e := &LoadError{SoundID: soundID(args)}
+
+	if howl != nil && howl.Type() == TypeObject {
+		if src := howl.Get("_src"); src.Type() == TypeString {
+			e.Source = src.String()
+		}
+	}
+
+	if len(args) < 2 {
+		return e
+	}
+	switch msg := args[1]; msg.Type() {
+	case TypeNumber:
+		e.Code = MediaErrorCode(msg.Int())
+		e.Err = e.Code.Err()
+		e.Message = "media error " + strconv.Itoa(int(e.Code))
+		if e.Err != nil {
+			e.Message = strings.TrimPrefix(e.Err.Error(), "howler: ")
+		}
+	default:
+		e.Message = message(msg)
+		switch {
+		case strings.HasPrefix(e.Message, "No audio support"):
+			e.Err = ErrNoAudio
+		case strings.HasPrefix(e.Message, "No codec support"),
+			strings.HasPrefix(e.Message, "Non-string found"):
+			e.Err = ErrUnsupportedCodec
+		case strings.HasPrefix(e.Message, "Failed loading audio file"):
+			e.Err = ErrNetwork
+			if m := statusPattern.FindStringSubmatch(e.Message); m != nil {
+				e.Status, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(e.Message, "Decoding audio data failed"):
+			e.Err = ErrDecode
+		}
+	}
+	return e
+}
+
+func newPlayError(args []Value) *PlayError {
+	e := &PlayError{SoundID: soundID(args)}
+	if len(args) < 2 {
+		return e
+	}
+	e.Message = message(args[1])
+	if strings.HasPrefix(e.Message, "Playback was unable to start") ||
+		(args[1].Type() == TypeObject && args[1].Get("name").Type() == TypeString && args[1].Get("name").String() == "NotAllowedError") {
+		e.Err = ErrLocked
+	}
+	return e
+}
+
+// soundID returns the sound id passed as the first argument to a listener, or
+// -1 if there wasn't one.
+func soundID(args []Value) int {
+	if len(args) > 0 && args[0].Type() == TypeNumber {
+		return args[0].Int()
+	}
+	return -1
+}
+
+// message returns the text of a message passed to an error listener, which
+// may be a string or an Error object.
+func message(msg Value) string {
+	if msg.Type() == TypeObject {
+		if m := msg.Get("message"); m.Type() == TypeString {
+			return m.String()
+		}
+	}
+	return msg.String()
+}
@@ -0,0 +1,132 @@
+package howler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewLoadError(t *testing.T) {
+	howl := fakeValueOf(map[string]any{"_src": "a.mp3"})
+	tests := []struct {
+		name string
+		howl Value
+		args []any
+		want LoadError
+	}{
+		{
+			name: "media error",
+			howl: howl,
+			args: []any{1001, int(MediaErrorDecode)},
+			want: LoadError{Code: MediaErrorDecode, SoundID: 1001, Source: "a.mp3", Message: "decoding audio data failed", Err: ErrDecode},
+		},
+		{
+			name: "unknown media error",
+			args: []any{nil, 9},
+			want: LoadError{Code: 9, SoundID: -1, Message: "media error 9"},
+		},
+		{
+			name: "no codec",
+			args: []any{nil, "No codec support for selected audio sources."},
+			want: LoadError{SoundID: -1, Message: "No codec support for selected audio sources.", Err: ErrUnsupportedCodec},
+		},
+		{
+			name: "no audio",
+			args: []any{nil, "No audio support."},
+			want: LoadError{SoundID: -1, Message: "No audio support.", Err: ErrNoAudio},
+		},
+		{
+			name: "http status",
+			howl: howl,
+			args: []any{nil, "Failed loading audio file with status: 404."},
+			want: LoadError{SoundID: -1, Status: 404, Source: "a.mp3", Message: "Failed loading audio file with status: 404.", Err: ErrNetwork},
+		},
+		{
+			name: "decoding",
+			args: []any{nil, map[string]any{"message": "Decoding audio data failed."}},
+			want: LoadError{SoundID: -1, Message: "Decoding audio data failed.", Err: ErrDecode},
+		},
+		{
+			name: "unknown message",
+			args: []any{nil, "Something else."},
+			want: LoadError{SoundID: -1, Message: "Something else."},
+		},
+		{
+			name: "no arguments",
+			want: LoadError{SoundID: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]Value, len(tt.args))
+			for i, a := range tt.args {
+				args[i] = fakeValueOf(a)
+			}
+			got := eventError("loaderror", tt.howl, args)
+			var lerr *LoadError
+			if !errors.As(got, &lerr) {
+				t.Fatalf("eventError() = %T, want *LoadError", got)
+			}
+			if *lerr != tt.want {
+				t.Errorf("got %+v, want %+v", *lerr, tt.want)
+			}
+			if tt.want.Err != nil && !errors.Is(got, tt.want.Err) {
+				t.Errorf("errors.Is(%v, %v) = false", got, tt.want.Err)
+			}
+		})
+	}
+}
+
+func TestNewPlayError(t *testing.T) {
+	tests := []struct {
+		name string
+		args []any
+		want PlayError
+	}{
+		{
+			name: "locked",
+			args: []any{1001, "Playback was unable to start. This is most commonly an issue on mobile devices."},
+			want: PlayError{SoundID: 1001, Message: "Playback was unable to start. This is most commonly an issue on mobile devices.", Err: ErrLocked},
+		},
+		{
+			name: "not allowed",
+			args: []any{1002, map[string]any{"name": "NotAllowedError", "message": "play() failed"}},
+			want: PlayError{SoundID: 1002, Message: "play() failed", Err: ErrLocked},
+		},
+		{
+			name: "other",
+			args: []any{1003, "Oops"},
+			want: PlayError{SoundID: 1003, Message: "Oops"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]Value, len(tt.args))
+			for i, a := range tt.args {
+				args[i] = fakeValueOf(a)
+			}
+			var perr *PlayError
+			if err := eventError("playerror", nil, args); !errors.As(err, &perr) || *perr != tt.want {
+				t.Errorf("eventError() = %+v, want %+v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaErrorCode(t *testing.T) {
+	tests := []struct {
+		code MediaErrorCode
+		want error
+	}{
+		{MediaErrorAborted, ErrAborted},
+		{MediaErrorNetwork, ErrNetwork},
+		{MediaErrorDecode, ErrDecode},
+		{MediaErrorSrcNotSupported, ErrUnsupportedCodec},
+		{0, nil},
+		{5, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.code.Err(); got != tt.want {
+			t.Errorf("MediaErrorCode(%d).Err() = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
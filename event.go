@@ -0,0 +1,120 @@
+package howler
+
+// Event names an event fired by a Howl.
+type Event string
+
+const (
+	// EventLoad fires when the sound is loaded.
+	EventLoad Event = "load"
+	// EventLoadError fires when the sound is unable to load.
+	EventLoadError Event = "loaderror"
+	// EventPlayError fires when the sound is unable to play.
+	EventPlayError Event = "playerror"
+	// EventPlay fires when the sound begins playing.
+	EventPlay Event = "play"
+	// EventEnd fires when the sound finishes playing (if it is looping, it'll fire
+	// at the end of each loop).
+	EventEnd Event = "end"
+	// EventPause fires when the sound has been paused.
+	EventPause Event = "pause"
+	// EventStop fires when the sound has been stopped.
+	EventStop Event = "stop"
+	// EventMute fires when the sound has been muted/unmuted.
+	EventMute Event = "mute"
+	// EventVolume fires when the sound's volume has changed.
+	EventVolume Event = "volume"
+	// EventRate fires when the sound's playback rate has changed.
+	EventRate Event = "rate"
+	// EventSeek fires when the sound has been seeked.
+	EventSeek Event = "seek"
+	// EventFade fires when the current sound finishes fading in/out.
+	EventFade Event = "fade"
+	// EventUnlock fires when audio has been automatically unlocked through a
+	// touch/click event.
+	EventUnlock Event = "unlock"
+	// EventStereo fires when the current sound has the stereo panning changed.
+	EventStereo Event = "stereo"
+	// EventPos fires when the current sound has the listener position changed.
+	EventPos Event = "pos"
+	// EventOrientation fires when the current sound has the direction of the
+	// listener changed.
+	EventOrientation Event = "orientation"
+)
+
+// Subscription is a handle to an event listener added with Howl.On or
+// Howl.Once. Pass it to Howl.Off to remove the listener.
+type Subscription struct {
+	event Event
+	id    int
+	// sound is the sound the listener is limited to, or -1 for every sound.
+	sound int
+}
+
+// Event returns the event the subscription listens to.
+func (s Subscription) Event() Event {
+	return s.event
+}
+
+// On listens to event, calling handler every time it fires. The handler
+// receives the Sound the event fired for, or the Howl itself for events that
+// aren't tied to a single sound such as EventLoad and EventUnlock.
+func (h Howl) On(event Event, handler func(Sound)) Subscription {
+	return h.listen("on", event, -1, handler)
+}
+
+// Once is like On, but the listener is removed after the first time event
+// fires.
+func (h Howl) Once(event Event, handler func(Sound)) Subscription {
+	return h.listen("once", event, -1, handler)
+}
+
+// Off removes a listener previously added with On or Once and releases it.
+func (h Howl) Off(sub Subscription) {
+	if fn, ok := h.funcs.get(sub.id); ok {
+		if sub.sound >= 0 {
+			h.value.Call("off", string(sub.event), fn, sub.sound)
+		} else {
+			h.value.Call("off", string(sub.event), fn)
+		}
+		h.funcs.release(sub.id)
+	}
+}
+
+// listen adds a listener with the howler.js method on or once. If sound isn't
+// -1 the listener only hears event firing for that sound.
+func (h Howl) listen(method string, event Event, sound int, handler func(Sound)) Subscription {
+	var id int
+	id, fn := h.funcs.add(func(this Value, args []Value) {
+		if method == "once" {
+			// howler.js has already removed the listener by the time it fires.
+			h.funcs.release(id)
+		}
+		handler(h.sound(args))
+	})
+
+	if sound >= 0 {
+		h.value.Call(method, string(event), fn, sound)
+	} else {
+		h.value.Call(method, string(event), fn)
+	}
+
+	return Subscription{
+		event: event,
+		id:    id,
+		sound: sound,
+	}
+}
+
+// sound returns the Sound for the id passed as the first argument to an event
+// listener. Events that aren't tied to a single sound are given no id, in which
+// case the whole Howl is returned.
+func (h Howl) sound(args []Value) Sound {
+	if len(args) > 0 && args[0].Type() == TypeNumber {
+		return soundSpecific{
+			id:     args[0].Int(),
+			value:  h.value,
+			states: h.states,
+		}
+	}
+	return h
+}
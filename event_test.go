@@ -0,0 +1,75 @@
+package howler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnOnceOff(t *testing.T) {
+	b := newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}, Preload: Some(false)})
+
+	var loaded Sound
+	h.Once(EventLoad, func(s Sound) { loaded = s })
+	h.Load()
+	counts := make(map[string]int)
+	count := func(name string) func(Sound) {
+		return func(Sound) { counts[name]++ }
+	}
+	h.On(EventPlay, count("on"))
+	h.Once(EventPlay, count("once"))
+	removed := h.On(EventPlay, count("removed"))
+	h.On(EventPlay, count("kept"))
+	onceRemoved := h.Once(EventPlay, count("once removed"))
+	h.Off(removed)
+	h.Off(onceRemoved)
+
+	b.Flush()
+	if _, ok := loaded.(Howl); !ok {
+		t.Errorf("load listener given %T, want the Howl", loaded)
+	}
+	var played []int
+	h.On(EventPlay, func(s Sound) { played = append(played, s.ID()) })
+	first := h.Play()
+	b.Flush()
+	second := h.Play()
+	b.Flush()
+
+	want := map[string]int{"on": 2, "once": 1, "kept": 2}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("%s listener called %d times, want %d", name, counts[name], n)
+		}
+	}
+	for _, name := range []string{"removed", "once removed"} {
+		if counts[name] != 0 {
+			t.Errorf("%s listener called %d times after Off", name, counts[name])
+		}
+	}
+	if len(played) != 2 || played[0] != first.ID() || played[1] != second.ID() {
+		t.Errorf("play listener given sounds %v, want [%d %d]", played, first.ID(), second.ID())
+	}
+}
+
+func TestListenSound(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Second)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	first := h.Play()
+	b.Advance(500 * time.Millisecond)
+	second := h.Play()
+
+	var ended []int
+	sub := h.listen("once", EventEnd, second.ID(), func(s Sound) {
+		ended = append(ended, s.ID())
+	})
+	b.Advance(500 * time.Millisecond)
+	if len(ended) != 0 {
+		t.Fatalf("listener for sound %d heard sound %d end", second.ID(), first.ID())
+	}
+	b.Advance(500 * time.Millisecond)
+	if len(ended) != 1 || ended[0] != second.ID() {
+		t.Errorf("ended = %v, want [%d]", ended, second.ID())
+	}
+	h.Off(sub)
+}
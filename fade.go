@@ -0,0 +1,322 @@
+package howler
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+// FadeCurve describes the shape of a fade in. It maps how far through the
+// fade it is, from 0 to 1, to how far the volume has moved from the quieter
+// level to the louder one, also from 0 to 1. Fade outs follow the curve
+// backwards, so a sound faded out and back in with the same curve passes
+// through the same levels.
+type FadeCurve func(t float64) float64
+
+// FadeLinear changes the volume at a constant rate. It is the curve used by
+// Fade, and is left to howler.js's own fade unless the Howl is on a bus. A nil
+// FadeCurve does the same.
+func FadeLinear(t float64) float64 {
+	return t
+}
+
+// FadeExponential changes the volume slowly at the quiet end of the fade and
+// quickly at the loud end, which sounds closer to a steady change in loudness
+// than a linear fade.
+func FadeExponential(t float64) float64 {
+	return (math.Exp2(10*t) - 1) / 1023
+}
+
+// FadeEqualPower follows a quarter sine wave, so a sound fading out while
+// another fades in over the same time keeps the overall loudness steady.
+func FadeEqualPower(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// linear returns true if c is nil or FadeLinear, which howler.js can run
+// itself.
+func (c FadeCurve) linear() bool {
+	return c == nil || reflect.ValueOf(c).Pointer() == reflect.ValueOf(FadeLinear).Pointer()
+}
+
+// level returns the volume t of the way through a fade between from and to.
+func (c FadeCurve) level(from, to, t float64) float64 {
+	var v float64
+	if to >= from {
+		v = from + (to-from)*c(t)
+	} else {
+		v = to + (from-to)*c(1-t)
+	}
+	return math.Max(0, math.Min(1, v))
+}
+
+// fadeStep is how often fades with a curve other than FadeLinear change the
+// volume.
+const fadeStep = 10 * time.Millisecond
+
+// closedChan is returned for fades that finish immediately.
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// fadeWait is closed once every sound faded by one call has finished.
+type fadeWait struct {
+	remaining int
+	done      chan struct{}
+	// then is called once done has been closed.
+	then func()
+}
+
+// soundFade is the fade in progress on a single sound.
+type soundFade struct {
+	wait *fadeWait
+	// stepper steps the volume from Go, or is nil if howler.js runs the fade.
+	stepper *fadeStepper
+	// emitted is set once a stepped fade has fired its fade event.
+	emitted bool
+}
+
+// fadeStepper steps the volume of a sound or a whole Howl along a curve.
+type fadeStepper struct {
+	howl     Value
+	id       int
+	from, to float64
+	curve    FadeCurve
+	interval time.Duration
+	steps    int
+	step     int
+	timer    Timer
+	// live counts the sounds whose fade is still this one.
+	live int
+}
+
+// fade fades sound id, or every sound of howl if id is -1, returning a channel
+// that is closed once they have all finished or been interrupted. If then
+// isn't nil it is called once the channel is closed.
+func (s *soundStates) fade(howl Value, id int, from, to float64, d time.Duration, curve FadeCurve, then func()) <-chan struct{} {
+	// howler.js ignores fades to or from volumes out of range.
+	if from < 0 || from > 1 || to < 0 || to > 1 {
+		return finished(then)
+	}
+
+	var ids []int
+	if id >= 0 {
+		if !howl.Call("_soundById", id).Truthy() {
+			return finished(then)
+		}
+		ids = []int{id}
+	} else {
+		ids = soundIDs(howl)
+	}
+
+	// howler.js never ends a fade that doesn't change the volume, so those
+	// and fades with no duration just set it.
+	if from == to || d <= 0 {
+		s.interrupt(howl, id)
+		s.setVolume(howl, id, to, false)
+		return finished(then)
+	}
+
+	// Fades on a bus are stepped from Go so that they follow changes to the
+	// bus volumes while they run.
+	if curve.linear() {
+		curve = FadeLinear
+		if !s.mixed() {
+			curve = nil
+		}
+	}
+
+	if curve == nil {
+		done := s.begin(howl, ids, nil, then)
+		if id >= 0 {
+			howl.Call("fade", from, to, d.Milliseconds(), id)
+		} else {
+			howl.Call("fade", from, to, d.Milliseconds())
+		}
+		return done
+	}
+
+	if len(ids) == 0 {
+		s.setVolume(howl, -1, to, false)
+		return finished(then)
+	}
+
+	steps := int(math.Max(1, math.Ceil(float64(d)/float64(fadeStep))))
+	st := &fadeStepper{
+		howl:     howl,
+		id:       id,
+		from:     from,
+		to:       to,
+		curve:    curve,
+		interval: d / time.Duration(steps),
+		steps:    steps,
+	}
+	done := s.begin(howl, ids, st, then)
+
+	// Setting the volume stops any fade howler.js is running on the sounds.
+	s.setVolume(howl, id, from, false)
+	s.schedule(st)
+	return done
+}
+
+// begin records a new fade on each of ids, interrupting the fades already in
+// progress on them. It returns a channel that is closed once every new fade
+// has finished, after which then is called.
+func (s *soundStates) begin(howl Value, ids []int, st *fadeStepper, then func()) <-chan struct{} {
+	if len(ids) == 0 {
+		return finished(then)
+	}
+	var emit []int
+	var thens []func()
+	wait := &fadeWait{remaining: len(ids), done: make(chan struct{}), then: then}
+
+	s.mu.Lock()
+	for _, id := range ids {
+		if old := s.fades[id]; old != nil {
+			// The interrupted fade still fires its fade event, which mustn't
+			// be mistaken for the end of the new one.
+			if then := s.finish(old); then != nil {
+				thens = append(thens, then)
+			}
+			s.skips[id]++
+			if s.detach(old) {
+				emit = append(emit, id)
+			}
+		}
+		if st != nil {
+			st.live++
+		}
+		s.fades[id] = &soundFade{wait: wait, stepper: st}
+	}
+	s.mu.Unlock()
+
+	for _, id := range emit {
+		howl.Call("_emit", string(EventFade), id)
+	}
+	for _, then := range thens {
+		then()
+	}
+	return wait.done
+}
+
+// finish marks f as finished, closing the channel returned for its fade once
+// every other sound faded along with it has finished too. It returns the
+// function to call once s.mu has been released, if any. The caller must hold
+// s.mu.
+func (s *soundStates) finish(f *soundFade) func() {
+	f.wait.remaining--
+	if f.wait.remaining > 0 {
+		return nil
+	}
+	close(f.wait.done)
+	return f.wait.then
+}
+
+// finished calls then, if it isn't nil, for a fade that finished immediately.
+func finished(then func()) <-chan struct{} {
+	if then != nil {
+		then()
+	}
+	return closedChan
+}
+
+// detach stops f following its stepper, returning true if it still needs its
+// fade event firing. The caller must hold s.mu.
+func (s *soundStates) detach(f *soundFade) bool {
+	st := f.stepper
+	if st == nil || f.emitted {
+		return false
+	}
+	f.emitted = true
+	st.live--
+	if st.live == 0 && st.timer != nil {
+		st.timer.Stop()
+	}
+	return true
+}
+
+// schedule arranges for the next step of st.
+func (s *soundStates) schedule(st *fadeStepper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st.live > 0 {
+		st.timer = s.backend.AfterFunc(st.interval, func() { s.tick(st) })
+	}
+}
+
+// tick moves st on by one step, firing the fade event of each sound still
+// following it once it reaches the end.
+func (s *soundStates) tick(st *fadeStepper) {
+	s.mu.Lock()
+	st.step++
+	live := st.live
+	s.mu.Unlock()
+	if live == 0 {
+		return
+	}
+
+	v := st.curve.level(st.from, st.to, float64(st.step)/float64(st.steps))
+	s.setVolume(st.howl, st.id, v, true)
+
+	if st.step < st.steps {
+		s.schedule(st)
+		return
+	}
+
+	var emit []int
+	s.mu.Lock()
+	for id, f := range s.fades {
+		if f.stepper == st && s.detach(f) {
+			emit = append(emit, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range emit {
+		st.howl.Call("_emit", string(EventFade), id)
+	}
+}
+
+// interrupt stops the fades stepped from Go on sound id, or on every sound if
+// id is -1, as howler.js does for its own fades when the volume is set or a
+// sound is stopped.
+func (s *soundStates) interrupt(howl Value, id int) {
+	if s == nil {
+		return
+	}
+	var emit []int
+	s.mu.Lock()
+	for soundID, f := range s.fades {
+		if (id < 0 || soundID == id) && s.detach(f) {
+			emit = append(emit, soundID)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range emit {
+		howl.Call("_emit", string(EventFade), id)
+	}
+}
+
+// fadeEnded handles a fade event fired for sound id.
+func (s *soundStates) fadeEnded(id int) {
+	var then func()
+	s.mu.Lock()
+	if s.skips[id] > 0 {
+		s.skips[id]--
+		if s.skips[id] == 0 {
+			delete(s.skips, id)
+		}
+	} else if f := s.fades[id]; f != nil {
+		s.detach(f)
+		then = s.finish(f)
+		delete(s.fades, id)
+	}
+	s.mu.Unlock()
+
+	if then != nil {
+		then()
+	}
+}
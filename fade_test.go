@@ -0,0 +1,125 @@
+package howler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFadeCurves(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve FadeCurve
+		mid   float64
+	}{
+		{"linear", FadeLinear, 0.5},
+		{"exponential", FadeExponential, (math.Exp2(5) - 1) / 1023},
+		{"equal power", FadeEqualPower, math.Sqrt2 / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve(0); math.Abs(got) > 1e-9 {
+				t.Errorf("curve(0) = %v, want 0", got)
+			}
+			if got := tt.curve(1); math.Abs(got-1) > 1e-9 {
+				t.Errorf("curve(1) = %v, want 1", got)
+			}
+			if got := tt.curve(0.5); math.Abs(got-tt.mid) > 1e-9 {
+				t.Errorf("curve(0.5) = %v, want %v", got, tt.mid)
+			}
+			// Fading out follows the curve backwards.
+			if got := tt.curve.level(1, 0, 0.25); math.Abs(got-tt.curve(0.75)) > 1e-9 {
+				t.Errorf("level(1, 0, 0.25) = %v, want %v", got, tt.curve(0.75))
+			}
+		})
+	}
+
+	if !FadeCurve(nil).linear() || !FadeCurve(FadeLinear).linear() || FadeCurve(FadeEqualPower).linear() {
+		t.Error("linear() doesn't tell FadeLinear and nil apart from other curves")
+	}
+}
+
+func TestFade(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to float64
+		d        time.Duration
+		curve    FadeCurve
+		// mid is the volume halfway through, or -1 if the fade should
+		// finish straight away.
+		mid float64
+	}{
+		{name: "linear", from: 1, to: 0, d: time.Second, curve: FadeLinear, mid: 0.5},
+		{name: "nil curve", from: 0, to: 1, d: time.Second, mid: 0.5},
+		{name: "equal power", from: 0, to: 1, d: time.Second, curve: FadeEqualPower, mid: math.Sqrt2 / 2},
+		{name: "same volume", from: 0.5, to: 0.5, d: time.Second, mid: -1},
+		{name: "same volume with curve", from: 0.5, to: 0.5, d: time.Second, curve: FadeExponential, mid: -1},
+		{name: "no duration", from: 1, to: 0.2, d: 0, mid: -1},
+		{name: "negative duration", from: 1, to: 0.2, d: -time.Second, curve: FadeEqualPower, mid: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.SetDuration("a.mp3", 10*time.Second)
+			h := New(HowlOptions{Source: []string{"a.mp3"}})
+			sound := h.Play()
+			b.Flush()
+
+			done := sound.FadeWith(tt.from, tt.to, tt.d, tt.curve)
+			b.Flush()
+			if tt.mid < 0 {
+				if !isClosed(done) {
+					t.Fatal("fade didn't finish straight away")
+				}
+				if got := sound.Volume(); got != tt.to {
+					t.Errorf("Volume() = %v, want %v", got, tt.to)
+				}
+				return
+			}
+
+			b.Advance(tt.d / 2)
+			if got := sound.Volume(); math.Abs(got-tt.mid) > 0.02 {
+				t.Errorf("Volume() halfway = %v, want %v", got, tt.mid)
+			}
+			if isClosed(done) {
+				t.Error("fade finished halfway through")
+			}
+			b.Advance(tt.d / 2)
+			if !isClosed(done) {
+				t.Error("fade didn't finish")
+			}
+			if got := sound.Volume(); math.Abs(got-tt.to) > 1e-9 {
+				t.Errorf("Volume() = %v, want %v", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestFadeInterrupted(t *testing.T) {
+	for _, curve := range []FadeCurve{FadeLinear, FadeEqualPower} {
+		b := newTestBackend(t)
+		h := New(HowlOptions{Source: []string{"a.mp3"}})
+		sound := h.Play()
+		b.Flush()
+
+		first := sound.FadeWith(1, 0, time.Second, curve)
+		b.Advance(100 * time.Millisecond)
+		second := sound.FadeWith(0.5, 0.5, time.Second, curve)
+		b.Flush()
+		if !isClosed(first) || !isClosed(second) {
+			t.Errorf("fades closed = %v, %v; want both", isClosed(first), isClosed(second))
+		}
+		if got := sound.Volume(); got != 0.5 {
+			t.Errorf("Volume() = %v, want 0.5", got)
+		}
+	}
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
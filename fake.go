@@ -0,0 +1,1466 @@
+package howler
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FakeBackend is an in-memory Backend that mimics howler.js closely enough to
+// exercise code using this package without a browser. Like howler.js, event
+// listeners are never called synchronously; they are queued and run by Flush.
+//
+// Playback runs against a virtual clock that only moves when Advance is
+// called, so seek positions, fades, sprite boundaries and the events they
+// fire happen at deterministic times. It is safe for concurrent use.
+type FakeBackend struct {
+	mu        sync.Mutex
+	howler    *fakeHowler
+	howls     []*fakeHowl
+	counter   int
+	durations map[string]time.Duration
+	codecs    map[string]bool
+	pending   []fakeCall
+	now       time.Duration
+	loadDelay time.Duration
+	failures  map[string]MediaErrorCode
+	locked    bool
+	timers    []*fakeTimer
+	ctx       *fakeAudioContext
+	master    *fakeNode
+}
+
+// NewFakeBackend returns a FakeBackend with no sounds loaded.
+func NewFakeBackend() *FakeBackend {
+	b := &FakeBackend{
+		// howler.js numbers sounds from 1000, which keeps ids from being
+		// mistaken for volumes.
+		counter:   1000,
+		durations: make(map[string]time.Duration),
+		failures:  make(map[string]MediaErrorCode),
+		codecs: map[string]bool{
+			"mp3":  true,
+			"mpeg": true,
+			"opus": true,
+			"ogg":  true,
+			"oga":  true,
+			"wav":  true,
+			"aac":  true,
+			"m4a":  true,
+			"m4b":  true,
+			"mp4":  true,
+			"webm": true,
+			"weba": true,
+			"flac": true,
+		},
+	}
+	b.howler = &fakeHowler{
+		b: b,
+		props: map[string]any{
+			"usingWebAudio": true,
+			"noAudio":       false,
+			"autoUnlock":    true,
+			"html5PoolSize": 10,
+			"autoSuspend":   true,
+		},
+		volume:      1,
+		pos:         []any{0.0, 0.0, 0.0},
+		orientation: []any{0.0, 0.0, -1.0, 0.0, 1.0, 0.0},
+	}
+	b.ctx = &fakeAudioContext{b: b, destination: b.newNode("AudioDestinationNode", nil, nil)}
+	b.master = b.newNode("GainNode", map[string]float64{"gain": 1}, nil)
+	b.master.outputs = []*fakeNode{b.ctx.destination}
+	return b
+}
+
+// SetDuration sets the duration of the audio file at src. Sources that haven't
+// been given a duration load with a duration of zero.
+func (b *FakeBackend) SetDuration(src string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.durations[src] = d
+}
+
+// SetCodec sets whether the audio format with the given extension is
+// supported. Common formats are supported by default.
+func (b *FakeBackend) SetCodec(ext string, supported bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.codecs[strings.ToLower(ext)] = supported
+}
+
+// FailLoad makes loading the audio file at src fail with code, as HTML5 Audio
+// would report it. A code of zero makes it load successfully again.
+func (b *FakeBackend) FailLoad(src string, code MediaErrorCode) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if code == 0 {
+		delete(b.failures, src)
+	} else {
+		b.failures[src] = code
+	}
+}
+
+// SetLocked sets whether audio is locked, as it is in browsers before the
+// first user interaction. Sounds played while locked fail with a playerror
+// event.
+func (b *FakeBackend) SetLocked(locked bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.locked = locked
+	if !locked {
+		for _, h := range b.howls {
+			h.emit(EventUnlock, nil, nil)
+		}
+	}
+}
+
+// Flush runs the event listeners queued since the last flush and the timers
+// that are due, including any queued by the listeners and timers themselves.
+func (b *FakeBackend) Flush() {
+	for {
+		b.mu.Lock()
+		pending := b.pending
+		b.pending = nil
+		timers := b.due()
+		b.mu.Unlock()
+
+		if len(pending) == 0 && len(timers) == 0 {
+			return
+		}
+		for _, call := range pending {
+			call.fn.call(call.this, call.args)
+		}
+		for _, t := range timers {
+			t.fn()
+		}
+	}
+}
+
+func (b *FakeBackend) Howler() Value {
+	return b.howler
+}
+
+func (b *FakeBackend) NewHowl(options Value) Value {
+	o, ok := options.(*fakeObject)
+	if !ok {
+		panic(fmt.Errorf("howler: fake backend given foreign options %T", options))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h := newFakeHowl(b, o.copyProps())
+	b.howls = append(b.howls, h)
+	if h.preload {
+		h.load()
+	}
+	return h
+}
+
+func (b *FakeBackend) NewObject() Value {
+	return &fakeObject{props: make(map[string]any)}
+}
+
+func (b *FakeBackend) FuncOf(fn func(this Value, args []Value)) Func {
+	return &fakeFunc{fn: fn}
+}
+
+// fakeCall is an event listener waiting to be run by Flush.
+type fakeCall struct {
+	fn   *fakeFunc
+	this Value
+	args []Value
+}
+
+// fakeFunc is a Func created by a FakeBackend.
+type fakeFunc struct {
+	fn       func(this Value, args []Value)
+	released int32
+}
+
+func (f *fakeFunc) Release() {
+	atomic.StoreInt32(&f.released, 1)
+}
+
+func (f *fakeFunc) call(this Value, args []Value) {
+	if atomic.LoadInt32(&f.released) != 0 {
+		panic("howler: call to released function")
+	}
+	f.fn(this, args)
+}
+
+// fakeUndefined is the JavaScript undefined value.
+type fakeUndefined struct{}
+
+// fakeValue is a primitive or array value held by a FakeBackend. Numbers are
+// always stored as float64 and arrays as []any.
+type fakeValue struct {
+	v any
+}
+
+// fakeValueOf converts a Go value into a Value the way js.ValueOf would.
+func fakeValueOf(x any) Value {
+	switch x := x.(type) {
+	case Value:
+		return x
+	case []any:
+		if x == nil {
+			return fakeValue{nil}
+		}
+		return fakeValue{x}
+	case nil, fakeUndefined, bool, string, *fakeFunc:
+		return fakeValue{x}
+	case map[string]any:
+		return &fakeObject{props: x}
+	case int:
+		return fakeValue{float64(x)}
+	case int32:
+		return fakeValue{float64(x)}
+	case int64:
+		return fakeValue{float64(x)}
+	case float32:
+		return fakeValue{float64(x)}
+	case float64:
+		return fakeValue{x}
+	default:
+		panic(fmt.Errorf("howler: fake backend can't convert %T", x))
+	}
+}
+
+func (v fakeValue) Get(key string) Value {
+	if arr, ok := v.v.([]any); ok && key == "length" {
+		return fakeValue{float64(len(arr))}
+	}
+	panic(fmt.Errorf("howler: Get(%q) on %s", key, v.Type()))
+}
+
+func (v fakeValue) Set(key string, value any) {
+	panic(fmt.Errorf("howler: Set(%q) on %s", key, v.Type()))
+}
+
+func (v fakeValue) Call(method string, args ...any) Value {
+	panic(fmt.Errorf("howler: Call(%q) on %s", method, v.Type()))
+}
+
+func (v fakeValue) Index(i int) Value {
+	arr, ok := v.v.([]any)
+	if !ok {
+		panic(fmt.Errorf("howler: Index on %s", v.Type()))
+	}
+	if i < 0 || i >= len(arr) {
+		return fakeValue{fakeUndefined{}}
+	}
+	return fakeValueOf(arr[i])
+}
+
+func (v fakeValue) Length() int {
+	arr, ok := v.v.([]any)
+	if !ok {
+		panic(fmt.Errorf("howler: Length on %s", v.Type()))
+	}
+	return len(arr)
+}
+
+func (v fakeValue) Keys() []string {
+	arr, ok := v.v.([]any)
+	if !ok {
+		panic(fmt.Errorf("howler: Keys on %s", v.Type()))
+	}
+	keys := make([]string, len(arr))
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+func (v fakeValue) Type() Type {
+	switch v.v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case float64:
+		return TypeNumber
+	case string:
+		return TypeString
+	case []any:
+		return TypeObject
+	case *fakeFunc:
+		return TypeFunction
+	default:
+		return TypeUndefined
+	}
+}
+
+func (v fakeValue) Truthy() bool {
+	switch x := v.v.(type) {
+	case bool:
+		return x
+	case float64:
+		return x != 0 && x == x
+	case string:
+		return x != ""
+	case []any, *fakeFunc:
+		return true
+	default:
+		return false
+	}
+}
+
+func (v fakeValue) Bool() bool {
+	if x, ok := v.v.(bool); ok {
+		return x
+	}
+	panic(fmt.Errorf("howler: Bool on %s", v.Type()))
+}
+
+func (v fakeValue) Int() int {
+	return int(v.Float())
+}
+
+func (v fakeValue) Float() float64 {
+	if x, ok := v.v.(float64); ok {
+		return x
+	}
+	panic(fmt.Errorf("howler: Float on %s", v.Type()))
+}
+
+func (v fakeValue) String() string {
+	switch x := v.v.(type) {
+	case string:
+		return x
+	case nil:
+		return "<null>"
+	case fakeUndefined:
+		return "<undefined>"
+	default:
+		return fmt.Sprintf("<%s>", v.Type())
+	}
+}
+
+// fakeObject is a plain object held by a FakeBackend.
+type fakeObject struct {
+	mu    sync.Mutex
+	props map[string]any
+}
+
+func (o *fakeObject) Get(key string) Value {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if v, ok := o.props[key]; ok {
+		return fakeValueOf(v)
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (o *fakeObject) Set(key string, value any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.props[key] = value
+}
+
+func (o *fakeObject) Call(method string, args ...any) Value {
+	v, _ := o.Get(method).(fakeValue)
+	fn, ok := v.v.(*fakeFunc)
+	if !ok {
+		panic(fmt.Errorf("howler: %s is not a function", method))
+	}
+	values := make([]Value, len(args))
+	for i, arg := range args {
+		values[i] = fakeValueOf(arg)
+	}
+	fn.call(o, values)
+	return fakeValue{fakeUndefined{}}
+}
+
+// Keys returns the object's properties in sorted order, as a map doesn't
+// remember the order they were added in.
+func (o *fakeObject) Keys() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	keys := make([]string, 0, len(o.props))
+	for k := range o.props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (o *fakeObject) Index(i int) Value { panic("howler: Index on object") }
+func (o *fakeObject) Length() int       { panic("howler: Length on object") }
+func (o *fakeObject) Type() Type        { return TypeObject }
+func (o *fakeObject) Truthy() bool      { return true }
+func (o *fakeObject) Bool() bool        { panic("howler: Bool on object") }
+func (o *fakeObject) Int() int          { panic("howler: Int on object") }
+func (o *fakeObject) Float() float64    { panic("howler: Float on object") }
+func (o *fakeObject) String() string    { return "<object>" }
+
+// copyProps returns a shallow copy of the object's properties.
+func (o *fakeObject) copyProps() map[string]any {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	props := make(map[string]any, len(o.props))
+	for k, v := range o.props {
+		props[k] = v
+	}
+	return props
+}
+
+// fakeHowler is the global Howler object of a FakeBackend.
+type fakeHowler struct {
+	b           *FakeBackend
+	props       map[string]any
+	volume      float64
+	muted       bool
+	pos         []any
+	orientation []any
+}
+
+func (g *fakeHowler) Get(key string) Value {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if g.b.webAudio() {
+		switch key {
+		case "ctx":
+			return g.b.ctx
+		case "masterGain":
+			return g.b.master
+		}
+	}
+	if v, ok := g.props[key]; ok {
+		return fakeValueOf(v)
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (g *fakeHowler) Set(key string, value any) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	g.props[key] = value
+}
+
+func (g *fakeHowler) Call(method string, args ...any) Value {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	return fakeValueOf(g.call(method, args))
+}
+
+func (g *fakeHowler) call(method string, args []any) any {
+	switch method {
+	case "volume":
+		if vol, ok := fakeNumber(fakeArg(args, 0)); ok {
+			if vol >= 0 && vol <= 1 {
+				g.volume = vol
+			}
+			return g
+		}
+		return g.volume
+	case "mute":
+		g.muted = fakeBool(fakeArg(args, 0))
+		return g
+	case "stop":
+		for _, h := range g.b.howls {
+			h.stop(nil)
+		}
+		return g
+	case "unload":
+		for _, h := range g.b.howls {
+			h.unload()
+		}
+		g.b.howls = nil
+		return g
+	case "codecs":
+		ext, _ := fakeArg(args, 0).(string)
+		return g.b.codecs[strings.ToLower(ext)]
+	case "stereo":
+		for _, h := range g.b.howls {
+			h.call("stereo", args)
+		}
+		return g
+	case "pos":
+		x, ok := fakeNumber(fakeArg(args, 0))
+		if !ok {
+			return g.pos
+		}
+		y, ok := fakeNumber(fakeArg(args, 1))
+		if !ok {
+			y = g.pos[1].(float64)
+		}
+		z, ok := fakeNumber(fakeArg(args, 2))
+		if !ok {
+			z = g.pos[2].(float64)
+		}
+		g.pos = []any{x, y, z}
+		return g
+	case "orientation":
+		x, ok := fakeNumber(fakeArg(args, 0))
+		if !ok {
+			return g.orientation
+		}
+		o := []any{x, 0.0, 0.0, 0.0, 0.0, 0.0}
+		for i := 1; i < 6; i++ {
+			if v, ok := fakeNumber(fakeArg(args, i)); ok {
+				o[i] = v
+			} else {
+				o[i] = g.orientation[i]
+			}
+		}
+		g.orientation = o
+		return g
+	default:
+		panic(fmt.Errorf("howler: Howler.%s is not a function", method))
+	}
+}
+
+func (g *fakeHowler) Index(i int) Value { panic("howler: Index on object") }
+func (g *fakeHowler) Length() int       { panic("howler: Length on object") }
+func (g *fakeHowler) Keys() []string    { panic("howler: Keys on Howler") }
+func (g *fakeHowler) Type() Type        { return TypeObject }
+func (g *fakeHowler) Truthy() bool      { return true }
+func (g *fakeHowler) Bool() bool        { panic("howler: Bool on object") }
+func (g *fakeHowler) Int() int          { panic("howler: Int on object") }
+func (g *fakeHowler) Float() float64    { panic("howler: Float on object") }
+func (g *fakeHowler) String() string    { return "<Howler>" }
+
+// fakeListener is an event listener added to a fakeHowl.
+type fakeListener struct {
+	fn   *fakeFunc
+	id   int
+	once bool
+}
+
+// fakeSprite is a sprite definition, in milliseconds like howler.js.
+type fakeSprite struct {
+	offset   float64
+	duration float64
+	loop     bool
+}
+
+// fakeSprites is a live view of a fakeHowl's sprites, standing in for the
+// _sprite object of a Howl. Each sprite is an [offset, duration, loop] array.
+type fakeSprites struct {
+	h *fakeHowl
+}
+
+func (s fakeSprites) Get(key string) Value {
+	s.h.b.mu.Lock()
+	defer s.h.b.mu.Unlock()
+	sprite, ok := s.h.sprites[key]
+	if !ok {
+		return fakeValue{fakeUndefined{}}
+	}
+	return fakeValue{[]any{sprite.offset, sprite.duration, sprite.loop}}
+}
+
+func (s fakeSprites) Set(key string, value any) {
+	def, ok := value.([]any)
+	if !ok || len(def) < 2 {
+		panic(fmt.Errorf("howler: sprite %q must be an [offset, duration, loop] array", key))
+	}
+	offset, _ := fakeNumber(def[0])
+	duration, _ := fakeNumber(def[1])
+
+	s.h.b.mu.Lock()
+	defer s.h.b.mu.Unlock()
+	s.h.sprites[key] = fakeSprite{
+		offset:   offset,
+		duration: duration,
+		loop:     len(def) > 2 && fakeBool(def[2]),
+	}
+}
+
+func (s fakeSprites) Keys() []string {
+	s.h.b.mu.Lock()
+	defer s.h.b.mu.Unlock()
+	keys := make([]string, 0, len(s.h.sprites))
+	for k := range s.h.sprites {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s fakeSprites) Call(method string, args ...any) Value {
+	panic(fmt.Errorf("howler: %s is not a function", method))
+}
+
+func (s fakeSprites) Index(i int) Value { panic("howler: Index on object") }
+func (s fakeSprites) Length() int       { panic("howler: Length on object") }
+func (s fakeSprites) Type() Type        { return TypeObject }
+func (s fakeSprites) Truthy() bool      { return true }
+func (s fakeSprites) Bool() bool        { panic("howler: Bool on object") }
+func (s fakeSprites) Int() int          { panic("howler: Int on object") }
+func (s fakeSprites) Float() float64    { panic("howler: Float on object") }
+func (s fakeSprites) String() string    { return "<object>" }
+
+// fakeSound is a single playback of a fakeHowl.
+type fakeSound struct {
+	id          int
+	sprite      string
+	volume      float64
+	rate        float64
+	loop        bool
+	muted       bool
+	paused      bool
+	ended       bool
+	queued      bool
+	seek        float64
+	fade        *fakeFade
+	stereo      any
+	pos         []any
+	orientation []any
+	panner      map[string]any
+	// node is the sound's gain node, which is kept when the sound is
+	// recycled. It is nil for Howls using HTML5 Audio.
+	node *fakeNode
+}
+
+// fakeHowl is a Howl created by a FakeBackend.
+type fakeHowl struct {
+	b           *FakeBackend
+	src         string
+	sources     []string
+	formats     []string
+	state       string
+	loadAt      time.Duration
+	failed      bool
+	duration    float64
+	volume      float64
+	rate        float64
+	loop        bool
+	muted       bool
+	pool        int
+	preload     bool
+	autoplay    bool
+	sprites     map[string]fakeSprite
+	sounds      []*fakeSound
+	listeners   map[string][]fakeListener
+	stereo      any
+	pos         []any
+	orientation []any
+	panner      map[string]any
+	html5       bool
+	// buffer is the decoded audio handed to each sound as it starts.
+	buffer *fakeObject
+}
+
+func newFakeHowl(b *FakeBackend, o map[string]any) *fakeHowl {
+	h := &fakeHowl{
+		b:           b,
+		state:       "unloaded",
+		volume:      1,
+		rate:        1,
+		pool:        5,
+		preload:     true,
+		sprites:     make(map[string]fakeSprite),
+		listeners:   make(map[string][]fakeListener),
+		orientation: []any{1.0, 0.0, 0.0},
+		panner: map[string]any{
+			"coneInnerAngle": 360.0,
+			"coneOuterAngle": 360.0,
+			"coneOuterGain":  0.0,
+			"distanceModel":  "inverse",
+			"maxDistance":    10000.0,
+			"refDistance":    1.0,
+			"rolloffFactor":  1.0,
+			"panningModel":   "HRTF",
+		},
+	}
+
+	h.sources = fakeStrings(o["src"])
+	h.formats = fakeStrings(o["format"])
+	if v, ok := fakeNumber(o["volume"]); ok {
+		h.volume = v
+	}
+	if v, ok := fakeNumber(o["rate"]); ok && v != 0 {
+		h.rate = v
+	}
+	if v, ok := fakeNumber(o["pool"]); ok && v != 0 {
+		h.pool = int(v)
+	}
+	if v, ok := o["preload"].(bool); ok {
+		h.preload = v
+	} else if o["preload"] == "metadata" {
+		h.preload = true
+	}
+	h.loop = fakeBool(o["loop"])
+	h.html5 = fakeBool(o["html5"])
+	h.muted = fakeBool(o["mute"])
+	h.autoplay = fakeBool(o["autoplay"])
+	if v, ok := fakeNumber(o["stereo"]); ok {
+		h.stereo = v
+	}
+	if v, ok := o["pos"].([]any); ok && len(v) == 3 {
+		h.pos = fakeFloats(v)
+	}
+	if v, ok := o["orientation"].([]any); ok && len(v) == 3 {
+		h.orientation = fakeFloats(v)
+	}
+	for k := range h.panner {
+		if v, ok := o[k]; ok {
+			h.panner[k] = v
+		}
+	}
+	for name, sprite := range fakeProps(o["sprite"]) {
+		if def, ok := sprite.([]any); ok && len(def) >= 2 {
+			offset, _ := fakeNumber(def[0])
+			duration, _ := fakeNumber(def[1])
+			h.sprites[name] = fakeSprite{
+				offset:   offset,
+				duration: duration,
+				loop:     len(def) > 2 && fakeBool(def[2]),
+			}
+		}
+	}
+
+	for _, event := range []Event{
+		EventLoad, EventLoadError, EventPlayError, EventPlay, EventEnd, EventPause,
+		EventStop, EventMute, EventVolume, EventRate, EventSeek, EventFade,
+		EventUnlock, EventStereo, EventPos, EventOrientation,
+	} {
+		if fn := fakeFuncOf(o["on"+string(event)]); fn != nil {
+			h.listeners[string(event)] = []fakeListener{{fn: fn}}
+		}
+	}
+
+	return h
+}
+
+func (h *fakeHowl) Get(key string) Value {
+	h.b.mu.Lock()
+	defer h.b.mu.Unlock()
+	switch key {
+	case "_src":
+		return fakeValueOf(h.src)
+	case "_state":
+		return fakeValueOf(h.state)
+	case "_duration":
+		return fakeValueOf(h.duration)
+	case "_sprite":
+		return fakeSprites{h}
+	case "_webAudio":
+		return fakeValueOf(h.webAudio())
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (h *fakeHowl) Set(key string, value any) {
+	panic(fmt.Errorf("howler: fake Howl has no property %q", key))
+}
+
+func (h *fakeHowl) Call(method string, args ...any) Value {
+	h.b.mu.Lock()
+	defer h.b.mu.Unlock()
+	return fakeValueOf(h.call(method, args))
+}
+
+func (h *fakeHowl) call(method string, args []any) any {
+	switch method {
+	case "load":
+		h.load()
+		return h
+	case "unload":
+		h.unload()
+		for i, other := range h.b.howls {
+			if other == h {
+				h.b.howls = append(h.b.howls[:i], h.b.howls[i+1:]...)
+				break
+			}
+		}
+		return nil
+	case "state":
+		return h.state
+	case "play":
+		return h.play(fakeArg(args, 0))
+	case "pause":
+		for _, sound := range h.soundsFor(fakeArg(args, 0)) {
+			if !sound.paused {
+				sound.paused = true
+			}
+			h.emit(EventPause, sound.id, nil)
+		}
+		return h
+	case "stop":
+		h.stop(fakeArg(args, 0))
+		return h
+	case "playing":
+		if id, ok := fakeID(fakeArg(args, 0)); ok {
+			sound := h.soundByID(id)
+			return sound != nil && !sound.paused
+		}
+		for _, sound := range h.sounds {
+			if !sound.paused {
+				return true
+			}
+		}
+		return false
+	case "duration":
+		if id, ok := fakeID(fakeArg(args, 0)); ok {
+			if sound := h.soundByID(id); sound != nil {
+				return h.sprites[sound.sprite].duration / 1000
+			}
+		}
+		return h.duration
+	case "mute":
+		muted, ok := fakeArg(args, 0).(bool)
+		if !ok {
+			return h.muted
+		}
+		if _, hasID := fakeID(fakeArg(args, 1)); !hasID {
+			h.muted = muted
+		}
+		for _, sound := range h.soundsFor(fakeArg(args, 1)) {
+			sound.muted = muted
+			h.emit(EventMute, sound.id, nil)
+		}
+		return h
+	case "volume":
+		return h.property(EventVolume, args, 0, 1,
+			func() float64 { return h.volume },
+			func(v float64) { h.volume = v },
+			func(s *fakeSound) *float64 { return &s.volume })
+	case "rate":
+		return h.property(EventRate, args, 0.5, 4,
+			func() float64 { return h.rate },
+			func(v float64) { h.rate = v },
+			func(s *fakeSound) *float64 { return &s.rate })
+	case "fade":
+		from, _ := fakeNumber(fakeArg(args, 0))
+		to, _ := fakeNumber(fakeArg(args, 1))
+		length, _ := fakeNumber(fakeArg(args, 2))
+		_, hasID := fakeID(fakeArg(args, 3))
+		if from < 0 || from > 1 || to < 0 || to > 1 {
+			return h
+		}
+		if !hasID {
+			h.volume = from
+		}
+		for _, sound := range h.soundsFor(fakeArg(args, 3)) {
+			h.fade(sound, from, to, time.Duration(length)*time.Millisecond, !hasID)
+		}
+		return h
+	case "seek":
+		return h.seek(args)
+	case "loop":
+		switch len(args) {
+		case 0:
+			return h.loop
+		case 1:
+			if id, ok := fakeID(args[0]); ok {
+				if sound := h.soundByID(id); sound != nil {
+					return sound.loop
+				}
+				return false
+			}
+			h.loop = fakeBool(args[0])
+		}
+		for _, sound := range h.soundsFor(fakeArg(args, 1)) {
+			sound.loop = fakeBool(args[0])
+		}
+		return h
+	case "stereo":
+		if len(args) == 0 {
+			return h.stereo
+		}
+		if len(args) == 1 {
+			if id, ok := fakeID(args[0]); ok && h.soundByID(id) != nil {
+				return h.soundByID(id).stereo
+			}
+		}
+		pan, _ := fakeNumber(args[0])
+		if _, hasID := fakeID(fakeArg(args, 1)); !hasID {
+			h.stereo = pan
+		}
+		for _, sound := range h.soundsFor(fakeArg(args, 1)) {
+			sound.stereo = pan
+			h.emit(EventStereo, sound.id, nil)
+		}
+		return h
+	case "pos":
+		return h.vector(EventPos, args,
+			func() *[]any { return &h.pos },
+			func(s *fakeSound) *[]any { return &s.pos })
+	case "orientation":
+		return h.vector(EventOrientation, args,
+			func() *[]any { return &h.orientation },
+			func(s *fakeSound) *[]any { return &s.orientation })
+	case "pannerAttr":
+		return h.pannerAttr(args)
+	case "_soundById":
+		id, _ := fakeID(fakeArg(args, 0))
+		sound := h.soundByID(id)
+		if sound == nil {
+			return nil
+		}
+		props := map[string]any{
+			"_id":     float64(sound.id),
+			"_paused": sound.paused,
+			"_ended":  sound.ended,
+		}
+		if sound.node != nil {
+			props["_node"] = sound.node
+		}
+		return props
+	case "_emit":
+		event, _ := fakeArg(args, 0).(string)
+		var id any
+		if soundID, ok := fakeID(fakeArg(args, 1)); ok {
+			id = soundID
+		}
+		h.emit(Event(event), id, fakeArg(args, 2))
+		return h
+	case "_getSoundIds":
+		ids := make([]any, len(h.sounds))
+		for i, sound := range h.sounds {
+			ids[i] = float64(sound.id)
+		}
+		return ids
+	case "on", "once":
+		event, _ := fakeArg(args, 0).(string)
+		fn := fakeFuncOf(fakeArg(args, 1))
+		id, _ := fakeID(fakeArg(args, 2))
+		if fn != nil {
+			h.listeners[event] = append(h.listeners[event], fakeListener{fn: fn, id: id, once: method == "once"})
+		}
+		return h
+	case "off":
+		h.off(fakeArg(args, 0), fakeArg(args, 1), fakeArg(args, 2))
+		return h
+	default:
+		panic(fmt.Errorf("howler: Howl.%s is not a function", method))
+	}
+}
+
+func (h *fakeHowl) Index(i int) Value { panic("howler: Index on object") }
+func (h *fakeHowl) Length() int       { panic("howler: Length on object") }
+func (h *fakeHowl) Keys() []string    { panic("howler: Keys on Howl") }
+func (h *fakeHowl) Type() Type        { return TypeObject }
+func (h *fakeHowl) Truthy() bool      { return true }
+func (h *fakeHowl) Bool() bool        { panic("howler: Bool on object") }
+func (h *fakeHowl) Int() int          { panic("howler: Int on object") }
+func (h *fakeHowl) Float() float64    { panic("howler: Float on object") }
+func (h *fakeHowl) String() string    { return "<Howl>" }
+
+// emit queues the listeners for event. Like howler.js, load listeners are
+// always called and other listeners only if they match the sound id. A nil id
+// is passed to listeners as undefined.
+func (h *fakeHowl) emit(event Event, id any, msg any) {
+	if id == nil {
+		id = fakeUndefined{}
+	}
+	listeners := h.listeners[string(event)]
+	for i := len(listeners) - 1; i >= 0; i-- {
+		l := listeners[i]
+		if l.id == 0 || l.id == id || event == EventLoad {
+			h.b.pending = append(h.b.pending, fakeCall{
+				fn:   l.fn,
+				this: h,
+				args: []Value{fakeValueOf(id), fakeValueOf(msg)},
+			})
+			if l.once {
+				h.listeners[string(event)] = append(listeners[:i:i], listeners[i+1:]...)
+				listeners = h.listeners[string(event)]
+			}
+		}
+	}
+}
+
+func (h *fakeHowl) off(event, fn, id any) {
+	name, hasEvent := event.(string)
+	f := fakeFuncOf(fn)
+	soundID, _ := fakeID(id)
+
+	if !hasEvent {
+		for k := range h.listeners {
+			delete(h.listeners, k)
+		}
+		return
+	}
+	if f == nil && soundID == 0 {
+		delete(h.listeners, name)
+		return
+	}
+
+	listeners := h.listeners[name]
+	for i, l := range listeners {
+		if (f == nil || l.fn == f) && l.id == soundID {
+			h.listeners[name] = append(listeners[:i:i], listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (h *fakeHowl) load() {
+	if h.state != "unloaded" && !h.failed {
+		return
+	}
+
+	h.src = ""
+	for i, src := range h.sources {
+		ext := ""
+		if i < len(h.formats) {
+			ext = h.formats[i]
+		} else {
+			ext = strings.TrimPrefix(path.Ext(strings.SplitN(src, "?", 2)[0]), ".")
+		}
+		if h.b.codecs[strings.ToLower(ext)] {
+			h.src = src
+			break
+		}
+	}
+	if h.src == "" {
+		h.emit(EventLoadError, nil, "No codec support for selected audio sources.")
+		return
+	}
+
+	h.state = "loading"
+	h.failed = false
+	h.loadAt = h.b.now + h.b.loadDelay
+	if h.b.loadDelay <= 0 {
+		h.finishLoad()
+	}
+}
+
+// finishLoad completes loading, starting any sounds played in the meantime.
+// Loads that fail stay in the loading state, as they do in howler.js.
+func (h *fakeHowl) finishLoad() {
+	if code, ok := h.b.failures[h.src]; ok {
+		h.failed = true
+		h.emit(EventLoadError, nil, int(code))
+		return
+	}
+
+	h.duration = h.b.durations[h.src].Seconds()
+	if len(h.sprites) == 0 {
+		h.sprites["__default"] = fakeSprite{duration: h.duration * 1000}
+	}
+	h.state = "loaded"
+	h.buffer = &fakeObject{props: map[string]any{
+		"duration": h.duration,
+		"src":      h.src,
+	}}
+	h.emit(EventLoad, nil, nil)
+
+	for _, sound := range h.sounds {
+		if sound.queued {
+			sound.queued = false
+			h.start(sound, sound.sprite)
+		}
+	}
+	if h.autoplay {
+		h.play(nil)
+	}
+}
+
+func (h *fakeHowl) unload() {
+	h.stop(nil)
+	h.sounds = nil
+	h.listeners = make(map[string][]fakeListener)
+	h.state = "unloaded"
+	h.duration = 0
+}
+
+func (h *fakeHowl) play(arg any) any {
+	var sound *fakeSound
+
+	if id, ok := fakeID(arg); ok {
+		if sound = h.soundByID(id); sound == nil {
+			return nil
+		}
+	} else {
+		name, isSprite := arg.(string)
+		if !isSprite {
+			name = "__default"
+			var paused *fakeSound
+			count := 0
+			for _, s := range h.sounds {
+				if s.paused && !s.ended {
+					count++
+					paused = s
+				}
+			}
+			if count == 1 {
+				sound = paused
+			}
+		}
+		if h.state == "unloaded" {
+			h.load()
+		}
+		switch h.state {
+		case "loading":
+			if sound == nil {
+				sound = h.inactiveSound()
+				sound.sprite = name
+				sound.queued = true
+				sound.ended = false
+			}
+			return sound.id
+		case "loaded":
+			if _, ok := h.sprites[name]; !ok {
+				return nil
+			}
+		default:
+			return nil
+		}
+		if sound == nil {
+			sound = h.inactiveSound()
+			h.start(sound, name)
+			return sound.id
+		}
+	}
+
+	sound.paused = false
+	sound.ended = false
+	h.emit(EventPlay, sound.id, nil)
+	return sound.id
+}
+
+// start begins playing a fresh sound from the start of sprite.
+func (h *fakeHowl) start(sound *fakeSound, sprite string) {
+	sound.sprite = sprite
+	sound.seek = h.sprites[sprite].offset / 1000
+	sound.loop = sound.loop || h.sprites[sprite].loop
+	if h.b.locked {
+		h.emit(EventPlayError, sound.id, "Playback was unable to start. This is most commonly an issue "+
+			"on mobile devices and Chrome where playback was not within a user interaction.")
+		return
+	}
+	if sound.node != nil {
+		sound.node.props["bufferSource"] = h.b.newNode("AudioBufferSourceNode", nil, map[string]any{
+			"buffer": h.buffer,
+		})
+	}
+	sound.paused = false
+	sound.ended = false
+	h.emit(EventPlay, sound.id, nil)
+}
+
+// inactiveSound recycles an ended sound or creates a new one, draining the
+// pool of ended sounds in the same way as howler.js.
+func (h *fakeHowl) inactiveSound() *fakeSound {
+	if len(h.sounds) >= h.pool {
+		ended := 0
+		for _, s := range h.sounds {
+			if s.ended {
+				ended++
+			}
+		}
+		for i := len(h.sounds) - 1; i >= 0 && ended > h.pool; i-- {
+			if h.sounds[i].ended {
+				h.sounds = append(h.sounds[:i], h.sounds[i+1:]...)
+				ended--
+			}
+		}
+	}
+
+	var sound *fakeSound
+	for _, s := range h.sounds {
+		if s.ended {
+			sound = s
+			break
+		}
+	}
+	if sound == nil {
+		sound = &fakeSound{}
+		if h.webAudio() {
+			sound.node = h.b.newNode("GainNode", map[string]float64{"gain": 1}, nil)
+			sound.node.outputs = []*fakeNode{h.b.master}
+		}
+		h.sounds = append(h.sounds, sound)
+	}
+
+	h.b.counter++
+	*sound = fakeSound{
+		id:          h.b.counter,
+		sprite:      "__default",
+		volume:      h.volume,
+		rate:        h.rate,
+		loop:        h.loop,
+		muted:       h.muted,
+		paused:      true,
+		ended:       true,
+		stereo:      h.stereo,
+		pos:         h.pos,
+		orientation: h.orientation,
+		panner:      fakeCopy(h.panner),
+		node:        sound.node,
+	}
+	return sound
+}
+
+func (h *fakeHowl) stop(id any) {
+	for _, sound := range h.soundsFor(id) {
+		h.stopFade(sound)
+		sound.queued = false
+		sound.seek = h.sprites[sound.sprite].offset / 1000
+		sound.paused = true
+		sound.ended = true
+		h.emit(EventStop, sound.id, nil)
+	}
+}
+
+func (h *fakeHowl) seek(args []any) any {
+	var sound *fakeSound
+	value, hasValue := fakeNumber(fakeArg(args, 0))
+
+	switch len(args) {
+	case 0:
+		if len(h.sounds) > 0 {
+			sound = h.sounds[0]
+		}
+	case 1:
+		if id, ok := fakeID(args[0]); ok && h.soundByID(id) != nil {
+			sound = h.soundByID(id)
+			hasValue = false
+		} else if len(h.sounds) > 0 {
+			sound = h.sounds[0]
+		}
+	default:
+		if id, ok := fakeID(args[1]); ok {
+			sound = h.soundByID(id)
+		}
+	}
+
+	if sound == nil {
+		if hasValue {
+			return h
+		}
+		return 0.0
+	}
+	if !hasValue {
+		return sound.seek
+	}
+	sound.seek = value
+	h.emit(EventSeek, sound.id, nil)
+	return h
+}
+
+// property implements the getters and setters shared by volume and rate.
+func (h *fakeHowl) property(event Event, args []any, min, max float64, get func() float64, set func(float64), field func(*fakeSound) *float64) any {
+	var id any
+	switch len(args) {
+	case 0:
+		return get()
+	case 1:
+		if soundID, ok := fakeID(args[0]); ok && h.soundByID(soundID) != nil {
+			return *field(h.soundByID(soundID))
+		}
+	default:
+		id = args[1]
+	}
+
+	value, ok := fakeNumber(args[0])
+	if !ok || value < min || value > max {
+		return h
+	}
+	if _, hasID := fakeID(id); !hasID {
+		set(value)
+	}
+	internal := fakeBool(fakeArg(args, 2))
+	for _, sound := range h.soundsFor(id) {
+		if event == EventVolume && !internal {
+			h.stopFade(sound)
+		}
+		*field(sound) = value
+		h.emit(event, sound.id, nil)
+	}
+	return h
+}
+
+// vector implements the getters and setters shared by pos and orientation.
+func (h *fakeHowl) vector(event Event, args []any, group func() *[]any, field func(*fakeSound) *[]any) any {
+	id, hasID := fakeID(fakeArg(args, 3))
+	x, isSet := fakeNumber(fakeArg(args, 0))
+
+	if !isSet {
+		if hasID {
+			if sound := h.soundByID(id); sound != nil {
+				return *field(sound)
+			}
+			return nil
+		}
+		return *group()
+	}
+
+	y, ok := fakeNumber(fakeArg(args, 1))
+	if !ok {
+		y = 0
+	}
+	z, ok := fakeNumber(fakeArg(args, 2))
+	if !ok {
+		z = 0
+	}
+	v := []any{x, y, z}
+	if !hasID {
+		*group() = v
+	}
+	for _, sound := range h.soundsFor(fakeArg(args, 3)) {
+		*field(sound) = v
+		h.emit(event, sound.id, nil)
+	}
+	return h
+}
+
+func (h *fakeHowl) pannerAttr(args []any) any {
+	switch len(args) {
+	case 0:
+		return fakeCopy(h.panner)
+	case 1:
+		if id, ok := fakeID(args[0]); ok {
+			if sound := h.soundByID(id); sound != nil {
+				return fakeCopy(sound.panner)
+			}
+			return fakeCopy(h.panner)
+		}
+	}
+
+	var attr map[string]any
+	switch o := args[0].(type) {
+	case *fakeObject:
+		attr = o.copyProps()
+	case map[string]any:
+		attr = o
+	}
+	_, hasID := fakeID(fakeArg(args, 1))
+	if !hasID {
+		for k, v := range attr {
+			h.panner[k] = v
+		}
+	}
+	for _, sound := range h.soundsFor(fakeArg(args, 1)) {
+		for k, v := range attr {
+			sound.panner[k] = v
+		}
+	}
+	return h
+}
+
+func (h *fakeHowl) soundByID(id int) *fakeSound {
+	for _, sound := range h.sounds {
+		if sound.id == id {
+			return sound
+		}
+	}
+	return nil
+}
+
+// soundsFor returns the sound with the given id, or every sound if id isn't a
+// number.
+func (h *fakeHowl) soundsFor(id any) []*fakeSound {
+	if soundID, ok := fakeID(id); ok {
+		if sound := h.soundByID(soundID); sound != nil {
+			return []*fakeSound{sound}
+		}
+		return nil
+	}
+	return h.sounds
+}
+
+func fakeArg(args []any, i int) any {
+	if i < len(args) {
+		return args[i]
+	}
+	return fakeUndefined{}
+}
+
+func fakeNumber(x any) (float64, bool) {
+	switch x := x.(type) {
+	case int:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case fakeValue:
+		return fakeNumber(x.v)
+	}
+	return 0, false
+}
+
+func fakeID(x any) (int, bool) {
+	if n, ok := fakeNumber(x); ok && n > 0 && n == float64(int(n)) {
+		return int(n), true
+	}
+	return 0, false
+}
+
+func fakeBool(x any) bool {
+	if x == nil {
+		return false
+	}
+	return fakeValueOf(x).Truthy()
+}
+
+func fakeStrings(x any) []string {
+	switch x := x.(type) {
+	case string:
+		return []string{x}
+	case []any:
+		s := make([]string, 0, len(x))
+		for _, v := range x {
+			if v, ok := v.(string); ok {
+				s = append(s, v)
+			}
+		}
+		return s
+	}
+	return nil
+}
+
+func fakeFloats(x []any) []any {
+	v := make([]any, len(x))
+	for i := range x {
+		v[i], _ = fakeNumber(x[i])
+	}
+	return v
+}
+
+func fakeProps(x any) map[string]any {
+	switch x := x.(type) {
+	case map[string]any:
+		return x
+	case *fakeObject:
+		return x.copyProps()
+	}
+	return nil
+}
+
+func fakeFuncOf(x any) *fakeFunc {
+	switch x := x.(type) {
+	case *fakeFunc:
+		return x
+	case fakeValue:
+		return fakeFuncOf(x.v)
+	}
+	return nil
+}
+
+func fakeCopy(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// webAudio returns true if the backend is set up to use Web Audio. The caller
+// must hold b.mu.
+func (b *FakeBackend) webAudio() bool {
+	return fakeBool(b.howler.props["usingWebAudio"])
+}
+
+// webAudio returns true if the Howl plays through Web Audio rather than HTML5
+// Audio. The caller must hold h.b.mu.
+func (h *fakeHowl) webAudio() bool {
+	return !h.html5 && h.b.webAudio()
+}
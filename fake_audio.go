@@ -0,0 +1,259 @@
+package howler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// fakeAudioContext is the Web Audio context of a FakeBackend. It only models
+// how nodes are connected and the values of their parameters; no audio is
+// processed.
+type fakeAudioContext struct {
+	b           *FakeBackend
+	destination *fakeNode
+}
+
+// fakeNode is an AudioNode created by a fakeAudioContext.
+type fakeNode struct {
+	b       *FakeBackend
+	kind    string
+	params  map[string]*fakeParam
+	props   map[string]any
+	outputs []*fakeNode
+}
+
+// fakeParam is an AudioParam of a fakeNode. Scheduled changes take effect
+// straight away.
+type fakeParam struct {
+	b     *FakeBackend
+	value float64
+}
+
+// newNode returns a node of the given kind with the given parameter values.
+// The caller must hold b.mu or be setting the backend up.
+func (b *FakeBackend) newNode(kind string, params map[string]float64, props map[string]any) *fakeNode {
+	n := &fakeNode{
+		b:      b,
+		kind:   kind,
+		params: make(map[string]*fakeParam, len(params)),
+		props:  props,
+	}
+	if n.props == nil {
+		n.props = make(map[string]any)
+	}
+	for name, v := range params {
+		n.params[name] = &fakeParam{b: b, value: v}
+	}
+	return n
+}
+
+// SignalPath returns the nodes the signal of a single sound passes through
+// after leaving the sound's own node, ending with the destination. Where a
+// node leads to several others only the first is followed. It returns nil for
+// a Howl or a sound that doesn't exist.
+func (b *FakeBackend) SignalPath(sound Sound) []Value {
+	node, ok := SoundNode(sound).(*fakeNode)
+	if !ok {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var path []Value
+	seen := map[*fakeNode]bool{node: true}
+	for len(node.outputs) > 0 && !seen[node.outputs[0]] {
+		node = node.outputs[0]
+		seen[node] = true
+		path = append(path, node)
+	}
+	return path
+}
+
+func (c *fakeAudioContext) Get(key string) Value {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	switch key {
+	case "destination":
+		return c.destination
+	case "currentTime":
+		return fakeValueOf(c.b.now.Seconds())
+	case "sampleRate":
+		return fakeValueOf(44100.0)
+	case "state":
+		return fakeValueOf("running")
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (c *fakeAudioContext) Set(key string, value any) {
+	panic(fmt.Errorf("howler: fake AudioContext has no property %q", key))
+}
+
+func (c *fakeAudioContext) Call(method string, args ...any) Value {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	switch method {
+	case "createGain":
+		return c.b.newNode("GainNode", map[string]float64{"gain": 1}, nil)
+	case "createBiquadFilter":
+		return c.b.newNode("BiquadFilterNode", map[string]float64{
+			"frequency": 350,
+			"Q":         1,
+			"gain":      0,
+			"detune":    0,
+		}, map[string]any{"type": "lowpass"})
+	case "createConvolver":
+		return c.b.newNode("ConvolverNode", nil, map[string]any{
+			"buffer":    nil,
+			"normalize": true,
+		})
+	case "createDelay":
+		max, ok := fakeNumber(fakeArg(args, 0))
+		if !ok {
+			max = 1
+		}
+		return c.b.newNode("DelayNode", map[string]float64{"delayTime": 0}, map[string]any{
+			"maxDelayTime": max,
+		})
+	case "createDynamicsCompressor":
+		return c.b.newNode("DynamicsCompressorNode", map[string]float64{
+			"threshold": -24,
+			"knee":      30,
+			"ratio":     12,
+			"attack":    0.003,
+			"release":   0.25,
+		}, map[string]any{"reduction": 0.0})
+	default:
+		panic(fmt.Errorf("howler: AudioContext.%s is not a function", method))
+	}
+}
+
+func (c *fakeAudioContext) Index(i int) Value { panic("howler: Index on object") }
+func (c *fakeAudioContext) Length() int       { panic("howler: Length on object") }
+func (c *fakeAudioContext) Keys() []string    { panic("howler: Keys on AudioContext") }
+func (c *fakeAudioContext) Type() Type        { return TypeObject }
+func (c *fakeAudioContext) Truthy() bool      { return true }
+func (c *fakeAudioContext) Bool() bool        { panic("howler: Bool on object") }
+func (c *fakeAudioContext) Int() int          { panic("howler: Int on object") }
+func (c *fakeAudioContext) Float() float64    { panic("howler: Float on object") }
+func (c *fakeAudioContext) String() string    { return "<AudioContext>" }
+
+func (n *fakeNode) Get(key string) Value {
+	n.b.mu.Lock()
+	defer n.b.mu.Unlock()
+	if p, ok := n.params[key]; ok {
+		return p
+	}
+	if v, ok := n.props[key]; ok {
+		return fakeValueOf(v)
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (n *fakeNode) Set(key string, value any) {
+	n.b.mu.Lock()
+	defer n.b.mu.Unlock()
+	if _, ok := n.params[key]; ok {
+		panic(fmt.Errorf("howler: %s.%s is read-only", n.kind, key))
+	}
+	n.props[key] = value
+}
+
+func (n *fakeNode) Call(method string, args ...any) Value {
+	n.b.mu.Lock()
+	defer n.b.mu.Unlock()
+	switch method {
+	case "connect":
+		dest, ok := fakeArg(args, 0).(*fakeNode)
+		if !ok {
+			panic(fmt.Errorf("howler: %s.connect given %T", n.kind, fakeArg(args, 0)))
+		}
+		for _, out := range n.outputs {
+			if out == dest {
+				return dest
+			}
+		}
+		n.outputs = append(n.outputs, dest)
+		return dest
+	case "disconnect":
+		dest, ok := fakeArg(args, 0).(*fakeNode)
+		if !ok {
+			n.outputs = nil
+			return fakeValue{fakeUndefined{}}
+		}
+		for i, out := range n.outputs {
+			if out == dest {
+				n.outputs = append(n.outputs[:i:i], n.outputs[i+1:]...)
+				break
+			}
+		}
+		return fakeValue{fakeUndefined{}}
+	default:
+		panic(fmt.Errorf("howler: %s.%s is not a function", n.kind, method))
+	}
+}
+
+// Keys returns the node's parameters and properties in sorted order.
+func (n *fakeNode) Keys() []string {
+	n.b.mu.Lock()
+	defer n.b.mu.Unlock()
+	keys := make([]string, 0, len(n.params)+len(n.props))
+	for k := range n.params {
+		keys = append(keys, k)
+	}
+	for k := range n.props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (n *fakeNode) Index(i int) Value { panic("howler: Index on object") }
+func (n *fakeNode) Length() int       { panic("howler: Length on object") }
+func (n *fakeNode) Type() Type        { return TypeObject }
+func (n *fakeNode) Truthy() bool      { return true }
+func (n *fakeNode) Bool() bool        { panic("howler: Bool on object") }
+func (n *fakeNode) Int() int          { panic("howler: Int on object") }
+func (n *fakeNode) Float() float64    { panic("howler: Float on object") }
+func (n *fakeNode) String() string    { return "<" + n.kind + ">" }
+
+func (p *fakeParam) Get(key string) Value {
+	p.b.mu.Lock()
+	defer p.b.mu.Unlock()
+	if key == "value" {
+		return fakeValueOf(p.value)
+	}
+	return fakeValue{fakeUndefined{}}
+}
+
+func (p *fakeParam) Set(key string, value any) {
+	p.b.mu.Lock()
+	defer p.b.mu.Unlock()
+	if v, ok := fakeNumber(value); ok && key == "value" {
+		p.value = v
+	}
+}
+
+func (p *fakeParam) Call(method string, args ...any) Value {
+	p.b.mu.Lock()
+	defer p.b.mu.Unlock()
+	switch method {
+	case "setValueAtTime", "linearRampToValueAtTime", "exponentialRampToValueAtTime", "setTargetAtTime":
+		if v, ok := fakeNumber(fakeArg(args, 0)); ok {
+			p.value = v
+		}
+		return p
+	default:
+		panic(fmt.Errorf("howler: AudioParam.%s is not a function", method))
+	}
+}
+
+func (p *fakeParam) Index(i int) Value { panic("howler: Index on object") }
+func (p *fakeParam) Length() int       { panic("howler: Length on object") }
+func (p *fakeParam) Keys() []string    { return []string{"value"} }
+func (p *fakeParam) Type() Type        { return TypeObject }
+func (p *fakeParam) Truthy() bool      { return true }
+func (p *fakeParam) Bool() bool        { panic("howler: Bool on object") }
+func (p *fakeParam) Int() int          { panic("howler: Int on object") }
+func (p *fakeParam) Float() float64    { panic("howler: Float on object") }
+func (p *fakeParam) String() string    { return "<AudioParam>" }
@@ -0,0 +1,236 @@
+package howler
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// fakeFade is a fade in progress on a fakeSound.
+type fakeFade struct {
+	from   float64
+	to     float64
+	start  time.Duration
+	length time.Duration
+	group  bool
+}
+
+// fakeTimer is a call scheduled on a FakeBackend's virtual clock.
+type fakeTimer struct {
+	b    *FakeBackend
+	at   time.Duration
+	fn   func()
+	done bool
+}
+
+// AfterFunc schedules fn to be called once the virtual clock has moved on by
+// d. Rather than in its own goroutine, fn is called by the Flush or Advance
+// that finds it due.
+func (b *FakeBackend) AfterFunc(d time.Duration, fn func()) Timer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := &fakeTimer{b: b, at: b.now + d, fn: fn}
+	b.timers = append(b.timers, t)
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.b.mu.Lock()
+	defer t.b.mu.Unlock()
+	if t.done {
+		return false
+	}
+	t.done = true
+	for i, other := range t.b.timers {
+		if other == t {
+			t.b.timers = append(t.b.timers[:i], t.b.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// due removes and returns the timers due at the current time, earliest first.
+func (b *FakeBackend) due() []*fakeTimer {
+	var due []*fakeTimer
+	remaining := b.timers[:0]
+	for _, t := range b.timers {
+		if t.at <= b.now {
+			t.done = true
+			due = append(due, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	b.timers = remaining
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].at < due[j].at
+	})
+	return due
+}
+
+// Now returns the time on the backend's virtual clock.
+func (b *FakeBackend) Now() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now
+}
+
+// SetLoadDelay sets how long Howls created or loaded from now on take to load.
+// By default they finish loading immediately, although their load listeners
+// still only run on the next Flush or Advance.
+func (b *FakeBackend) SetLoadDelay(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.loadDelay = d
+}
+
+// Advance moves the virtual clock forward by d. Playing sounds progress at
+// their playback rate, fades step towards their target volume, sounds
+// reaching the end of their sprite loop or end, and timers fire when due.
+// Event listeners are run at the simulated time their event fires, so a
+// listener that starts another sound from an end event starts it exactly at
+// the end of the first.
+func (b *FakeBackend) Advance(d time.Duration) {
+	b.Flush()
+
+	b.mu.Lock()
+	target := b.now + d
+	b.mu.Unlock()
+
+	for {
+		b.mu.Lock()
+		next := b.next(target)
+		b.step(next - b.now)
+		b.now = next
+		b.fire()
+		b.mu.Unlock()
+
+		b.Flush()
+
+		if next >= target {
+			return
+		}
+	}
+}
+
+// next returns the earliest time up to target at which something happens.
+func (b *FakeBackend) next(target time.Duration) time.Duration {
+	next := target
+	for _, t := range b.timers {
+		if t.at < next {
+			next = t.at
+		}
+	}
+	for _, h := range b.howls {
+		if h.state == "loading" && !h.failed && h.loadAt < next {
+			next = h.loadAt
+		}
+		for _, sound := range h.sounds {
+			if sound.fade != nil {
+				if at := sound.fade.start + sound.fade.length; at < next {
+					next = at
+				}
+			}
+			if sound.paused || sound.rate <= 0 {
+				continue
+			}
+			sprite := h.sprites[sound.sprite]
+			if sound.loop && sprite.duration <= 0 {
+				continue
+			}
+			remaining := (sprite.offset+sprite.duration)/1000 - sound.seek
+			at := b.now + time.Duration(math.Ceil(math.Max(0, remaining)/sound.rate*float64(time.Second)))
+			if at < next {
+				next = at
+			}
+		}
+	}
+	if next < b.now {
+		next = b.now
+	}
+	return next
+}
+
+// step advances every playing sound and fade by d.
+func (b *FakeBackend) step(d time.Duration) {
+	now := b.now + d
+	for _, h := range b.howls {
+		for _, sound := range h.sounds {
+			if !sound.paused {
+				sound.seek += d.Seconds() * sound.rate
+			}
+			if f := sound.fade; f != nil {
+				progress := 1.0
+				if f.length > 0 {
+					progress = math.Min(1, float64(now-f.start)/float64(f.length))
+				}
+				sound.volume = f.from + (f.to-f.from)*progress
+				if f.group {
+					h.volume = sound.volume
+				}
+			}
+		}
+	}
+}
+
+// fire handles everything due at the current time.
+func (b *FakeBackend) fire() {
+	for _, h := range b.howls {
+		if h.state == "loading" && !h.failed && h.loadAt <= b.now {
+			h.finishLoad()
+		}
+		for _, sound := range h.sounds {
+			if f := sound.fade; f != nil && f.start+f.length <= b.now {
+				sound.fade = nil
+				sound.volume = f.to
+				h.emit(EventFade, sound.id, nil)
+			}
+			if sound.paused {
+				continue
+			}
+			sprite := h.sprites[sound.sprite]
+			start := sprite.offset / 1000
+			end := (sprite.offset + sprite.duration) / 1000
+			if sound.seek < end-1e-9 || (sound.loop && sprite.duration <= 0) {
+				continue
+			}
+			if sound.loop || sprite.loop {
+				sound.seek = start + math.Mod(sound.seek-end, end-start)
+			} else {
+				sound.seek = start
+				sound.paused = true
+				sound.ended = true
+			}
+			h.emit(EventEnd, sound.id, nil)
+		}
+	}
+}
+
+// fade starts fading sound from one volume to another, replacing any fade
+// already in progress.
+func (h *fakeHowl) fade(sound *fakeSound, from, to float64, length time.Duration, group bool) {
+	h.stopFade(sound)
+	sound.volume = from
+	sound.fade = &fakeFade{
+		from:   from,
+		to:     to,
+		start:  h.b.now,
+		length: length,
+		group:  group,
+	}
+	if length <= 0 {
+		sound.fade = nil
+		sound.volume = to
+		h.emit(EventFade, sound.id, nil)
+	}
+}
+
+// stopFade ends the fade in progress on sound, jumping to its target volume.
+func (h *fakeHowl) stopFade(sound *fakeSound) {
+	if f := sound.fade; f != nil {
+		sound.fade = nil
+		sound.volume = f.to
+		h.emit(EventFade, sound.id, nil)
+	}
+}
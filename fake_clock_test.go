@@ -0,0 +1,73 @@
+package howler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockTimers(t *testing.T) {
+	b := NewFakeBackend()
+	var fired []time.Duration
+	record := func() { fired = append(fired, b.Now()) }
+
+	b.AfterFunc(300*time.Millisecond, record)
+	b.AfterFunc(100*time.Millisecond, func() {
+		record()
+		// Timers scheduled by timers fire in the same Advance if due.
+		b.AfterFunc(50*time.Millisecond, record)
+	})
+	stopped := b.AfterFunc(200*time.Millisecond, record)
+	if !stopped.Stop() {
+		t.Error("Stop() of a pending timer = false")
+	}
+	if stopped.Stop() {
+		t.Error("second Stop() = true")
+	}
+	now := b.AfterFunc(0, record)
+
+	b.Flush()
+	if len(fired) != 1 || fired[0] != 0 {
+		t.Fatalf("after Flush fired at %v, want [0s]", fired)
+	}
+	if now.Stop() {
+		t.Error("Stop() of a fired timer = true")
+	}
+
+	b.Advance(time.Second)
+	want := []time.Duration{0, 100 * time.Millisecond, 150 * time.Millisecond, 300 * time.Millisecond}
+	if len(fired) != len(want) {
+		t.Fatalf("fired at %v, want %v", fired, want)
+	}
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Fatalf("fired at %v, want %v", fired, want)
+		}
+	}
+	if b.Now() != time.Second {
+		t.Errorf("Now() = %v, want 1s", b.Now())
+	}
+}
+
+func TestFakeClockEvents(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Second)
+	b.SetDuration("b.mp3", time.Second)
+	first := New(HowlOptions{Source: []string{"a.mp3"}})
+	second := New(HowlOptions{Source: []string{"b.mp3"}})
+
+	// A listener starting a sound from an end event starts it exactly at the
+	// end of the first, even when Advance jumps past it.
+	var started time.Duration
+	first.Once(EventEnd, func(Sound) {
+		started = b.Now()
+		second.Play()
+	})
+	first.Play()
+	b.Advance(1500 * time.Millisecond)
+	if started != time.Second {
+		t.Errorf("end listener ran at %v, want 1s", started)
+	}
+	if s := second.Sounds(); len(s) != 1 || s[0].Seek() != 500*time.Millisecond {
+		t.Errorf("second sound isn't 500ms in")
+	}
+}
@@ -0,0 +1,160 @@
+package howler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestBackend installs a FakeBackend for the duration of a test, unloading
+// everything created on it afterwards.
+func newTestBackend(t *testing.T) *FakeBackend {
+	t.Helper()
+	b := NewFakeBackend()
+	old := CurrentBackend()
+	SetBackend(b)
+	t.Cleanup(func() {
+		Unload()
+		SetVoiceLimit(VoiceLimit{})
+		SetBackend(old)
+	})
+	return b
+}
+
+func TestFakeLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(b *FakeBackend)
+		delay  time.Duration
+		err    error
+		loaded bool
+		// state is the state the Howl is left in. Loads that fail stay
+		// loading, unless no source could be chosen.
+		state State
+	}{
+		{name: "immediate", loaded: true, state: StateLoaded},
+		{name: "delayed", delay: time.Second, loaded: true, state: StateLoaded},
+		{name: "failure", setup: func(b *FakeBackend) { b.FailLoad("a.mp3", MediaErrorNetwork) }, err: ErrNetwork, state: StateLoading},
+		{name: "no codec", setup: func(b *FakeBackend) { b.SetCodec("mp3", false) }, err: ErrUnsupportedCodec, state: StateUnloaded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			if tt.setup != nil {
+				tt.setup(b)
+			}
+			b.SetLoadDelay(tt.delay)
+			var loaded bool
+			var loadErr error
+			h := New(HowlOptions{
+				Source:      []string{"a.mp3"},
+				OnLoad:      func() { loaded = true },
+				OnLoadError: func(err error) { loadErr = err },
+			})
+			if loaded || loadErr != nil {
+				t.Fatal("listener called before Flush")
+			}
+			if tt.delay > 0 {
+				b.Advance(tt.delay - time.Millisecond)
+				if loaded {
+					t.Fatal("loaded before the delay passed")
+				}
+			}
+			b.Advance(time.Millisecond)
+			if loaded != tt.loaded || !errors.Is(loadErr, tt.err) {
+				t.Errorf("loaded, error = %v, %v; want %v, %v", loaded, loadErr, tt.loaded, tt.err)
+			}
+			if h.State() != tt.state {
+				t.Errorf("State() = %v, want %v", h.State(), tt.state)
+			}
+		})
+	}
+}
+
+func TestFakePlayback(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", 2*time.Second)
+	var ends int
+	h := New(HowlOptions{
+		Source: []string{"a.mp3"},
+		OnEnd:  func() { ends++ },
+	})
+	if h.Duration() != 2*time.Second {
+		t.Errorf("Duration() = %v, want 2s", h.Duration())
+	}
+
+	s := h.Play()
+	b.Advance(500 * time.Millisecond)
+	if got := s.Seek(); got != 500*time.Millisecond {
+		t.Errorf("Seek() = %v, want 500ms", got)
+	}
+	s.SetRate(2)
+	b.Advance(250 * time.Millisecond)
+	if got := s.Seek(); got != time.Second {
+		t.Errorf("Seek() at double rate = %v, want 1s", got)
+	}
+	s.Pause()
+	b.Advance(time.Second)
+	if s.Playing() || s.Seek() != time.Second {
+		t.Errorf("paused: playing, seek = %v, %v; want false, 1s", s.Playing(), s.Seek())
+	}
+	s.Play()
+	b.Advance(500 * time.Millisecond)
+	if s.Playing() || ends != 1 {
+		t.Errorf("at the end: playing, ends = %v, %d; want false, 1", s.Playing(), ends)
+	}
+
+	sprites := New(HowlOptions{
+		Source:  []string{"a.mp3"},
+		Sprites: map[string]Sprite{"loop": {Offset: 500 * time.Millisecond, Duration: 500 * time.Millisecond, Loop: true}},
+		OnEnd:   func() { ends++ },
+	})
+	loop, err := sprites.PlaySprite("loop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Advance(1250 * time.Millisecond)
+	if !loop.Playing() || ends != 3 {
+		t.Errorf("looping sprite: playing, ends = %v, %d; want true, 3", loop.Playing(), ends)
+	}
+	if got := loop.Seek(); got != 750*time.Millisecond {
+		t.Errorf("looping sprite Seek() = %v, want 750ms", got)
+	}
+}
+
+func TestFakeLocked(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetLocked(true)
+	var playErr error
+	var unlocked bool
+	h := New(HowlOptions{
+		Source:      []string{"a.mp3"},
+		OnPlayError: func(err error) { playErr = err },
+		OnUnlock:    func() { unlocked = true },
+	})
+	h.Play()
+	b.Flush()
+	if !errors.Is(playErr, ErrLocked) {
+		t.Errorf("play error = %v, want %v", playErr, ErrLocked)
+	}
+	b.SetLocked(false)
+	b.Flush()
+	if !unlocked {
+		t.Error("unlock event didn't fire")
+	}
+}
+
+func TestFakeReleasedFunc(t *testing.T) {
+	b := newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	var calls int
+	sub := h.On(EventPlay, func(Sound) { calls++ })
+	h.Play()
+	b.Flush()
+	h.Off(sub)
+	h.Play()
+	b.Flush()
+	if calls != 1 {
+		t.Errorf("listener called %d times, want 1", calls)
+	}
+}
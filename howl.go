@@ -1,31 +1,47 @@
-//go:build js && wasm
-
 package howler
 
 import (
-	"syscall/js"
+	"fmt"
 	"time"
 )
 
-var howl = js.Global().Get("Howl")
-
 func New(opts HowlOptions) Howl {
-	var tmp = js.Global().Get("Object").New()
-
-	tmp.Set("src", opts.Source)
-	tmp.Set("format", opts.Format)
-	tmp.Set("volume", opts.Volume)
-	tmp.Set("html5", opts.HTML5)
-	tmp.Set("loop", opts.Loop)
-	tmp.Set("preload", opts.Preload)
-	tmp.Set("autoplay", opts.Autoplay)
-	tmp.Set("mute", opts.Mute)
-	tmp.Set("rate", opts.Rate)
-	tmp.Set("pool", opts.Pool)
-	tmp.Set("xhr", opts.XHR)
-	tmp.Set("orientation", opts.Orientation)
-	tmp.Set("stereo", opts.Stereo)
-	tmp.Set("pos", opts.Pos)
+	var tmp = backend.NewObject()
+	var funcs = newCallbacks(backend)
+	var state = &howlState{funcs: funcs}
+	state.load.reset()
+
+	tmp.Set("src", anySlice(opts.Source))
+	if len(opts.Format) > 0 {
+		tmp.Set("format", anySlice(opts.Format))
+	}
+	setOptional(tmp, "volume", opts.Volume)
+	setOptional(tmp, "html5", opts.HTML5)
+	setOptional(tmp, "loop", opts.Loop)
+	setOptional(tmp, "preload", opts.Preload)
+	if opts.PreloadMetadata {
+		tmp.Set("preload", "metadata")
+	}
+	setOptional(tmp, "autoplay", opts.Autoplay)
+	setOptional(tmp, "mute", opts.Mute)
+	setOptional(tmp, "rate", opts.Rate)
+	setOptional(tmp, "pool", opts.Pool)
+	if opts.XHR != nil {
+		tmp.Set("xhr", opts.XHR.object())
+	}
+	if orientation, ok := opts.Orientation.Get(); ok {
+		tmp.Set("orientation", []any{orientation.X, orientation.Y, orientation.Z})
+	}
+	setOptional(tmp, "stereo", opts.Stereo)
+	if pos, ok := opts.Pos.Get(); ok {
+		tmp.Set("pos", []any{pos.X, pos.Y, pos.Z})
+	}
+
+	// howler.js reads the initial panner attributes from the top level of the
+	// options rather than from a pannerAttr object.
+	for k, v := range opts.PannerOptions.attributes() {
+		tmp.Set(k, v)
+	}
 
 	if opts.Sprites != nil {
 		sprites := make(map[string]any)
@@ -35,26 +51,49 @@ func New(opts HowlOptions) Howl {
 		tmp.Set("sprite", sprites)
 	}
 
-	setCallback(tmp, "onload", opts.OnLoad)
-	setCallback(tmp, "onloaderror", opts.OnLoadError)
-	setCallback(tmp, "onplayerror", opts.OnPlayError)
-	setCallback(tmp, "onplay", opts.OnPlay)
-	setCallback(tmp, "onend", opts.OnEnd)
-	setCallback(tmp, "onpause", opts.OnPause)
-	setCallback(tmp, "onstop", opts.OnStop)
-	setCallback(tmp, "onmute", opts.OnMute)
-	setCallback(tmp, "onvolume", opts.OnVolume)
-	setCallback(tmp, "onrate", opts.OnRate)
-	setCallback(tmp, "onseek", opts.OnSeek)
-	setCallback(tmp, "onfade", opts.OnFade)
-	setCallback(tmp, "onunlock", opts.OnUnlock)
-	setCallback(tmp, "onstereo", opts.OnStereo)
-	setCallback(tmp, "onpos", opts.OnPos)
-	setCallback(tmp, "onorientation", opts.OnOrientation)
+	setCallback(funcs, tmp, "onload", CallbackFunc(func() {
+		state.load.finish(nil)
+		if opts.OnLoad != nil {
+			opts.OnLoad()
+		}
+	}))
+	setCallback(funcs, tmp, "onloaderror", CallbackErrorFunc(func(err error) {
+		state.load.finish(err)
+		if opts.OnLoadError != nil {
+			opts.OnLoadError(err)
+		}
+	}))
+	setCallback(funcs, tmp, "onplayerror", opts.OnPlayError)
+	setCallback(funcs, tmp, "onplay", opts.OnPlay)
+	setCallback(funcs, tmp, "onend", opts.OnEnd)
+	setCallback(funcs, tmp, "onpause", opts.OnPause)
+	setCallback(funcs, tmp, "onstop", opts.OnStop)
+	setCallback(funcs, tmp, "onmute", opts.OnMute)
+	setCallback(funcs, tmp, "onvolume", opts.OnVolume)
+	setCallback(funcs, tmp, "onrate", opts.OnRate)
+	setCallback(funcs, tmp, "onseek", opts.OnSeek)
+	setCallback(funcs, tmp, "onfade", opts.OnFade)
+	setCallback(funcs, tmp, "onunlock", opts.OnUnlock)
+	setCallback(funcs, tmp, "onstereo", opts.OnStereo)
+	setCallback(funcs, tmp, "onpos", opts.OnPos)
+	setCallback(funcs, tmp, "onorientation", opts.OnOrientation)
+
+	howl := backend.NewHowl(tmp)
+	funcs.attach(howl)
+	sounds := newSoundStates(backend)
+	sounds.limit = opts.Voices
+	sounds.priority = opts.Priority
+	sounds.track(howl, funcs)
+	sounds.register(howl)
 
-	return Howl{
-		soundGroup{howl.New(tmp)},
+	h := Howl{
+		soundGroup: soundGroup{howl, sounds},
+		howlState:  state,
 	}
+	if opts.Bus != nil {
+		opts.Bus.Add(h)
+	}
+	return h
 }
 
 type Sprite struct {
@@ -66,41 +105,72 @@ type Sprite struct {
 	Loop bool `json:"loop,omitempty"`
 }
 
+// XHR configures the requests howler.js makes to load audio files when using
+// Web Audio.
+type XHR struct {
+	// The HTTP method. default: GET
+	Method string `json:"method,omitempty"`
+	// Custom headers to send with the request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Whether to send credentials with cross-site requests.
+	WithCredentials bool `json:"withCredentials,omitempty"`
+}
+
+func (x XHR) object() map[string]any {
+	obj := map[string]any{
+		"withCredentials": x.WithCredentials,
+	}
+	if x.Method != "" {
+		obj["method"] = x.Method
+	}
+	if x.Headers != nil {
+		headers := make(map[string]any, len(x.Headers))
+		for k, v := range x.Headers {
+			headers[k] = v
+		}
+		obj["headers"] = headers
+	}
+	return obj
+}
+
 type HowlOptions struct {
 	// The sources to the track(s) to be loaded for the sound (URLs or base64 data
 	// URIs). These should be in order of preference, howler.js will automatically
 	// load the first one that is compatible with the current browser. If your files
 	// have no extensions, you will need to explicitly specify the extension using
 	// the format property.
-	Source []OptionalString `json:"src,omitempty"`
+	Source []string `json:"src,omitempty"`
 
 	// The volume of the specific track, from 0.0 to 1.0.
-	Volume OptionalFloat `json:"volume,omitempty"` // default=Howler's global volume
+	Volume Optional[float64] `json:"volume,omitzero"` // default=Howler's global volume
 
 	// Set to true to force HTML5 Audio. This should be used for large audio files so
 	// that you don't have to wait for the full file to be downloaded and decoded
 	// before playing.
-	HTML5 OptionalBool `json:"html5,omitempty"`
+	HTML5 Optional[bool] `json:"html5,omitzero"`
 
 	// Set to true to automatically loop the sound forever.
-	Loop OptionalBool `json:"loop,omitempty"`
+	Loop Optional[bool] `json:"loop,omitzero"`
+
+	// Automatically begin downloading the audio file when the Howl is defined.
+	Preload Optional[bool] `json:"preload,omitzero"` // default=true
 
-	// Automatically begin downloading the audio file when the Howl is defined. If
-	// using HTML5 Audio, you can set this to 'metadata' to only preload the file's
-	// metadata (to get its duration without download the entire file, for example).
-	Preload OptionalBool `json:"preload,omitempty"` // default=true
+	// If using HTML5 Audio, set this to only preload the file's metadata (to get
+	// its duration without download the entire file, for example). It takes
+	// precedence over Preload.
+	PreloadMetadata bool `json:"preload_metadata,omitempty"`
 
 	// Set to true to automatically start playback when sound is loaded.
-	Autoplay OptionalBool `json:"autoplay,omitempty"`
+	Autoplay Optional[bool] `json:"autoplay,omitzero"`
 
 	// Set to true to load the audio muted.
-	Mute OptionalBool `json:"mute,omitempty"`
+	Mute Optional[bool] `json:"mute,omitzero"`
 
 	// Define a sound sprite for the sound.
 	Sprites map[string]Sprite `json:"sprites,omitempty"`
 
 	// The rate of playback. 0.5 to 4.0, with 1.0 being normal speed.
-	Rate OptionalFloat `json:"rate,omitempty"` // default=1.0
+	Rate Optional[float64] `json:"rate,omitzero"` // default=1.0
 
 	// The size of the inactive sounds pool. Once sounds are stopped or finish
 	// playing, they are marked as ended and ready for cleanup. We keep a pool of
@@ -108,38 +178,49 @@ type HowlOptions struct {
 	// changed. It is important to keep in mind that when a sound is paused, it won't
 	// be removed from the pool and will still be considered active so that it can be
 	// resumed later.
-	Pool OptionalInt `json:"pool,omitempty"` // default=5
+	Pool Optional[int] `json:"pool,omitzero"` // default=5
 
 	// Howler.js automatically detects your file format from the extension, but you
 	// may also specify a format in situations where extraction won't work (such as
 	// with a SoundCloud stream).
-	Format []OptionalString `json:"format,omitempty"`
+	Format []string `json:"format,omitempty"`
 
 	// When using Web Audio, howler.js uses an XHR request to load the audio files.
 	// If you need to send custom headers, set the HTTP method or enable
 	// withCredentials (see reference), include them with this parameter. Each is
 	// optional (method defaults to GET, headers default to null and withCredentials
 	// defaults to false).
-	XHR js.Value `json:"-"`
+	XHR *XHR `json:"xhr,omitempty"`
 
 	// Sets the stereo panning value of the audio source for this sound or group.
 	// This makes it easy to setup left/right panning with a value of -1.0 being far
 	// left and a value of 1.0 being far right.
-	Stereo OptionalBool `json:"stereo,omitempty"`
+	Stereo Optional[float64] `json:"stereo,omitzero"`
 
 	// Sets the 3D spatial position of the audio source for this sound or group
 	// relative to the global listener.
-	Pos []OptionalFloat `json:"pos,omitempty"`
+	Pos Optional[Vec3] `json:"pos,omitzero"`
 
 	// Sets the direction the audio source is pointing in the 3D cartesian coordinate
 	// space. Depending on how directional the sound is, based on the cone
 	// attributes, a sound pointing away from the listener can be quiet or silent.
-	Orientation []OptionalFloat `json:"orientation,omitempty"`
+	Orientation Optional[Vec3] `json:"orientation,omitzero"`
 
 	// Sets the panner node's attributes for a sound or group of sounds. See the
 	// pannerAttr method for all available options.
 	PannerOptions PannerOptions `json:"panner_options"`
 
+	// Assigns the Howl to a mixer bus, which scales its volume. See Bus.
+	Bus *Bus `json:"-"`
+
+	// Limits how many of the Howl's sounds can play at once. See
+	// SetVoiceLimit for a limit shared by every Howl.
+	Voices VoiceLimit `json:"voices,omitzero"`
+
+	// The priority of the Howl's sounds when a voice limit steals using
+	// StealLowestPriority. Higher priorities win.
+	Priority int `json:"priority,omitempty"`
+
 	// Fires when the sound is loaded.
 	OnLoad CallbackFunc `json:"-"`
 	// Fires when the sound is unable to load.
@@ -175,31 +256,158 @@ type HowlOptions struct {
 	OnOrientation CallbackFunc `json:"-"`
 }
 
+// Howl is a group of sounds played from the same audio file. Howls are created
+// with New, NewChecked or NewAndLoad; the zero Howl must not be used.
 type Howl struct {
 	soundGroup
+	*howlState
+}
+
+// howlState is the Go side state shared by every copy of a Howl.
+type howlState struct {
+	funcs *callbacks
+	load  loadState
+	// err is the reason the Howl couldn't be created, if it is invalid.
+	err error
+}
+
+// invalidHowl returns a Howl standing in for one that couldn't be created
+// because of err. Its methods do nothing, its sounds fail to play, and Wait
+// returns err.
+func invalidHowl(err error) Howl {
+	state := &howlState{funcs: newCallbacks(backend), err: err}
+	state.load.reset()
+	state.load.finish(err)
+	return Howl{
+		soundGroup: soundGroup{value: nullValue{}, states: newSoundStates(backend)},
+		howlState:  state,
+	}
+}
+
+// Valid returns false if the Howl couldn't be created.
+func (h Howl) Valid() bool {
+	return h.howlState != nil && h.err == nil
+}
+
+// Err returns the reason the Howl couldn't be created, or nil if it is valid.
+func (h Howl) Err() error {
+	if h.howlState == nil {
+		return nil
+	}
+	return h.err
 }
 
 // Load is called by default, but if you set preload to false, you must call load
-// before you can play any sounds.
+// before you can play any sounds. Calling it again after loading has failed
+// tries again.
 func (h Howl) Load() {
+	if h.err != nil {
+		return
+	}
+	if h.State() != StateLoaded {
+		h.load.reset()
+	}
 	h.value.Call("load")
 }
 
-// PlaySprite will play the previously played sound (for example, after pausing
-// it). However, if an id of a sound that has been drained from the pool is
-// passed, nothing will play.
-func (h Howl) PlaySprite(name string) Sound {
-	if result := h.value.Call("play", name); result.Truthy() {
-		return soundSpecific{
-			id:    result.Int(),
-			value: h.soundGroup.value,
+// PlaySprite plays the named sprite, returning the new sound. It fails with
+// ErrUnknownSprite if the Howl has no sprite with that name, or with the
+// Howl's own error if it is invalid, in which case the returned Sound is
+// invalid.
+func (h Howl) PlaySprite(name string) (Sound, error) {
+	if err := h.Err(); err != nil {
+		return invalid(err)
+	}
+	if !h.sprites().Get(name).Truthy() {
+		return invalid(fmt.Errorf("%w %q", ErrUnknownSprite, name))
+	}
+	id, err := h.states.play(h.value, -1, func() int {
+		return playedID(h.value.Call("play", name))
+	})
+	if err != nil {
+		return invalid(fmt.Errorf("%w: sprite %q", err, name))
+	}
+	if id < 0 {
+		return invalid(fmt.Errorf("%w: sprite %q", ErrNotPlayed, name))
+	}
+	return soundSpecific{
+		id:     id,
+		value:  h.value,
+		states: h.states,
+	}, nil
+}
+
+// Sprites returns the Howl's sprites keyed by name. howler.js's own sprite
+// covering the whole file, added when a Howl without sprites loads, is left
+// out.
+func (h Howl) Sprites() map[string]Sprite {
+	obj := h.sprites()
+	sprites := make(map[string]Sprite)
+	for _, name := range obj.Keys() {
+		if name != defaultSprite {
+			sprites[name] = spriteOf(obj.Get(name))
 		}
 	}
-	return soundSpecific{id: -1}
+	return sprites
+}
+
+// Sprite returns the sprite with the given name.
+func (h Howl) Sprite(name string) (Sprite, bool) {
+	if name == defaultSprite {
+		return Sprite{}, false
+	}
+	def := h.sprites().Get(name)
+	if !def.Truthy() {
+		return Sprite{}, false
+	}
+	return spriteOf(def), true
+}
+
+// HasSprite returns true if the Howl has a sprite with the given name.
+func (h Howl) HasSprite(name string) bool {
+	_, ok := h.Sprite(name)
+	return ok
+}
+
+// SetSprite adds a sprite to the Howl or replaces the one with the same name.
+// Sounds already playing the sprite keep their old bounds. Adding a sprite to
+// a Howl created without any before it has loaded stops howler.js adding its
+// own sprite covering the whole file, so Play will no longer work.
+func (h Howl) SetSprite(name string, sprite Sprite) {
+	h.sprites().Set(name, []any{sprite.Offset.Milliseconds(), sprite.Duration.Milliseconds(), sprite.Loop})
+}
+
+// defaultSprite is the sprite howler.js plays when no sprite is named.
+const defaultSprite = "__default"
+
+// sprites returns the Howl's sprite definitions. howler.js keeps each as an
+// [offset, duration, loop] array in milliseconds.
+func (h Howl) sprites() Value {
+	return h.value.Get("_sprite")
+}
+
+func spriteOf(def Value) Sprite {
+	s := Sprite{
+		Offset:   time.Duration(def.Index(0).Float() * float64(time.Millisecond)),
+		Duration: time.Duration(def.Index(1).Float() * float64(time.Millisecond)),
+	}
+	if def.Length() > 2 {
+		s.Loop = def.Index(2).Truthy()
+	}
+	return s
 }
 
 // Unload and destroy a Howl object. This will immediately stop all sounds
-// attached to this sound and remove it from the cache.
+// attached to this sound and remove it from the cache. Every callback registered
+// on the Howl is released.
 func (h Howl) Unload() {
+	// Remove the listeners first, as howler.js fires stop events for the
+	// sounds it stops, which would otherwise run after they are released.
+	h.value.Call("off")
 	h.value.Call("unload")
+	unassign(h)
+	h.states.unchain()
+	h.states.unregister()
+	h.funcs.releaseAll()
+	h.states.clear()
 }
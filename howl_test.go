@@ -0,0 +1,76 @@
+package howler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSprites(t *testing.T) {
+	sprites := map[string]Sprite{
+		"jump": {Duration: 500 * time.Millisecond},
+		"loop": {Offset: time.Second, Duration: 2 * time.Second, Loop: true},
+	}
+	tests := []struct {
+		name    string
+		sprites map[string]Sprite
+	}{
+		{"none", nil},
+		{"some", sprites},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.SetDuration("a.mp3", 3*time.Second)
+			h := New(HowlOptions{Source: []string{"a.mp3"}, Sprites: tt.sprites})
+			b.Flush()
+
+			// howler.js's own sprite covering the whole file is hidden.
+			got := h.Sprites()
+			if len(got) != len(tt.sprites) {
+				t.Errorf("Sprites() = %v, want %v", got, tt.sprites)
+			}
+			for name, want := range tt.sprites {
+				if got[name] != want {
+					t.Errorf("Sprites()[%q] = %+v, want %+v", name, got[name], want)
+				}
+				if s, ok := h.Sprite(name); !ok || s != want {
+					t.Errorf("Sprite(%q) = %+v, %v; want %+v, true", name, s, ok, want)
+				}
+			}
+			if h.HasSprite(defaultSprite) {
+				t.Errorf("HasSprite(%q) = true", defaultSprite)
+			}
+
+			added := Sprite{Offset: 2500 * time.Millisecond, Duration: 250 * time.Millisecond}
+			h.SetSprite("coin", added)
+			if s, ok := h.Sprite("coin"); !ok || s != added {
+				t.Errorf("Sprite(\"coin\") after SetSprite = %+v, %v; want %+v, true", s, ok, added)
+			}
+			if len(h.Sprites()) != len(tt.sprites)+1 {
+				t.Errorf("Sprites() after SetSprite = %v", h.Sprites())
+			}
+			s, err := h.PlaySprite("coin")
+			if err != nil {
+				t.Fatalf("PlaySprite(\"coin\") = %v", err)
+			}
+			b.Advance(100 * time.Millisecond)
+			if !s.Playing() || s.Seek() != 2600*time.Millisecond {
+				t.Errorf("coin: playing, seek = %v, %v; want true, 2.6s", s.Playing(), s.Seek())
+			}
+		})
+	}
+}
+
+func TestPlaySpriteErrors(t *testing.T) {
+	newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	if s, err := h.PlaySprite("missing"); !errors.Is(err, ErrUnknownSprite) || s.Valid() {
+		t.Errorf("PlaySprite(\"missing\") = %v, %v; want an invalid Sound and %v", s, err, ErrUnknownSprite)
+	}
+
+	invalid, verr := NewChecked(HowlOptions{})
+	if _, err := invalid.PlaySprite("missing"); err != verr {
+		t.Errorf("PlaySprite() on an invalid Howl = %v, want %v", err, verr)
+	}
+}
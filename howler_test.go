@@ -0,0 +1,29 @@
+package howler
+
+import (
+	"testing"
+)
+
+func TestUnload(t *testing.T) {
+	b := newTestBackend(t)
+	var stops int
+	h := New(HowlOptions{Source: []string{"a.mp3"}, OnStop: func() { stops++ }})
+	h.On(EventStop, func(Sound) { stops++ })
+	h.Play()
+	b.Flush()
+
+	before := LiveCallbacks()
+	Unload()
+	// The stop events of the sounds howler.js stopped must not reach the
+	// released callbacks.
+	b.Flush()
+	if stops != 0 {
+		t.Errorf("stop listeners called %d times after Unload", stops)
+	}
+	if got := LiveCallbacks(); got >= before {
+		t.Errorf("LiveCallbacks() = %d after Unload, want fewer than %d", got, before)
+	}
+	if h.State() != StateUnloaded {
+		t.Errorf("State() = %v, want %v", h.State(), StateUnloaded)
+	}
+}
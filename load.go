@@ -0,0 +1,93 @@
+package howler
+
+import (
+	"context"
+	"sync"
+)
+
+// loadState tracks the outcome of the most recent attempt to load a Howl.
+type loadState struct {
+	mu   sync.Mutex
+	done chan struct{}
+	err  error
+}
+
+// reset prepares for a new attempt to load, unless one is still in progress.
+func (l *loadState) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done != nil {
+		select {
+		case <-l.done:
+		default:
+			return
+		}
+	}
+	l.done = make(chan struct{})
+	l.err = nil
+}
+
+// finish records the outcome of the current attempt to load.
+func (l *loadState) finish(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-l.done:
+	default:
+		l.err = err
+		close(l.done)
+	}
+}
+
+// wait returns a channel that is closed when the current attempt to load
+// finishes.
+func (l *loadState) wait() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.done
+}
+
+// result returns the error the current attempt to load finished with.
+func (l *loadState) result() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Wait blocks until the Howl has loaded, returning nil once it has, the
+// *LoadError if it fails to load, or ctx's error if ctx is done first. A Howl
+// created with Preload set to false won't start loading until Load or Play is
+// called.
+//
+// Wait must not be called from an event callback, since the event it is
+// waiting for can't fire until the callback returns. For the same reason, with
+// a FakeBackend it only returns once Flush or Advance has run the load event.
+func (h Howl) Wait(ctx context.Context) error {
+	if h.State() == StateLoaded {
+		return nil
+	}
+	select {
+	case <-h.load.wait():
+		return h.load.result()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewAndLoad validates opts, creates a Howl from them and waits for it to load.
+// If it fails to load or ctx is done first, the Howl is unloaded and an invalid
+// Howl returned along with the error.
+func NewAndLoad(ctx context.Context, opts HowlOptions) (Howl, error) {
+	h, err := NewChecked(opts)
+	if err != nil {
+		return h, err
+	}
+	if h.State() == StateUnloaded {
+		h.Load()
+	}
+	if err := h.Wait(ctx); err != nil {
+		h.Unload()
+		return invalidHowl(err), err
+	}
+	return h, nil
+}
@@ -0,0 +1,91 @@
+package howler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadRetry(t *testing.T) {
+	b := newTestBackend(t)
+	b.FailLoad("a.mp3", MediaErrorNetwork)
+	h := New(HowlOptions{Source: []string{"a.mp3"}, Preload: Some(false)})
+
+	h.Load()
+	b.Flush()
+	var lerr *LoadError
+	if err := h.Wait(context.Background()); !errors.As(err, &lerr) || lerr.Code != MediaErrorNetwork {
+		t.Fatalf("Wait() = %v, want a network LoadError", err)
+	}
+
+	b.FailLoad("a.mp3", 0)
+	h.Load()
+	b.Flush()
+	if err := h.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() after retrying = %v, want nil", err)
+	}
+	if h.State() != StateLoaded {
+		t.Errorf("State() = %v, want %v", h.State(), StateLoaded)
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetLoadDelay(time.Second)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want %v", err, context.Canceled)
+	}
+	b.Advance(time.Second)
+	if err := h.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestInvalidHowl(t *testing.T) {
+	b := newTestBackend(t)
+	tests := []struct {
+		name string
+		new  func() (Howl, error)
+	}{
+		{
+			name: "NewChecked",
+			new:  func() (Howl, error) { return NewChecked(HowlOptions{}) },
+		},
+		{
+			name: "NewAndLoad",
+			new: func() (Howl, error) {
+				b.FailLoad("bad.mp3", MediaErrorDecode)
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				return NewAndLoad(ctx, HowlOptions{Source: []string{"bad.mp3"}})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := tt.new()
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if h.Valid() || h.Err() != err {
+				t.Errorf("Valid(), Err() = %v, %v; want false, %v", h.Valid(), h.Err(), err)
+			}
+			if _, perr := h.TryPlay(); !errors.Is(perr, ErrNotPlayed) {
+				t.Errorf("TryPlay() = %v, want %v", perr, ErrNotPlayed)
+			}
+			h.Load()
+			if werr := h.Wait(context.Background()); werr != err {
+				t.Errorf("Wait() = %v, want %v", werr, err)
+			}
+			h.SetVolume(0.5)
+			h.Fade(0, 1, time.Second)
+			h.Stop()
+			h.Unload()
+		})
+	}
+}
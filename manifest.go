@@ -0,0 +1,268 @@
+package howler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes a set of named sounds in a form that can be edited
+// without recompiling, such as:
+//
+//	{
+//	  "sounds": {
+//	    "laser": {
+//	      "src": ["sfx.webm", "sfx.mp3"],
+//	      "sprites": {"pew": {"offset": 0, "duration": 250}},
+//	      "volume": 0.8,
+//	      "tags": ["sfx"]
+//	    }
+//	  }
+//	}
+//
+// Manifests can also be written in YAML, with the same field names.
+type Manifest struct {
+	Sounds map[string]ManifestSound `json:"sounds"`
+}
+
+// ManifestSound describes a single Howl in a Manifest. Unset fields take
+// howler's defaults.
+type ManifestSound struct {
+	Sources  []string                  `json:"src"`
+	Formats  []string                  `json:"format,omitempty"`
+	Sprites  map[string]ManifestSprite `json:"sprites,omitempty"`
+	Volume   Optional[float64]         `json:"volume,omitzero"`
+	Rate     Optional[float64]         `json:"rate,omitzero"`
+	Loop     Optional[bool]            `json:"loop,omitzero"`
+	HTML5    Optional[bool]            `json:"html5,omitzero"`
+	Preload  Optional[bool]            `json:"preload,omitzero"`
+	Autoplay Optional[bool]            `json:"autoplay,omitzero"`
+	Mute     Optional[bool]            `json:"mute,omitzero"`
+	Pool     Optional[int]             `json:"pool,omitzero"`
+	Stereo   Optional[float64]         `json:"stereo,omitzero"`
+	Pos      Optional[Vec3]            `json:"pos,omitzero"`
+	Panner   PannerOptions             `json:"panner,omitzero"`
+
+	// Tags group sounds so they can be looked up together, for example to
+	// assign every "music" sound to the same bus.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ManifestSprite is a sprite with its offset and duration in milliseconds.
+type ManifestSprite struct {
+	Offset   int64 `json:"offset"`
+	Duration int64 `json:"duration"`
+	Loop     bool  `json:"loop,omitempty"`
+}
+
+// Sprite converts the sprite into the form used by HowlOptions.
+func (s ManifestSprite) Sprite() Sprite {
+	return Sprite{
+		Offset:   time.Duration(s.Offset) * time.Millisecond,
+		Duration: time.Duration(s.Duration) * time.Millisecond,
+		Loop:     s.Loop,
+	}
+}
+
+// Options converts the sound into the options used to create its Howl.
+func (s ManifestSound) Options() HowlOptions {
+	opts := HowlOptions{
+		Source:        s.Sources,
+		Format:        s.Formats,
+		Volume:        s.Volume,
+		Rate:          s.Rate,
+		Loop:          s.Loop,
+		HTML5:         s.HTML5,
+		Preload:       s.Preload,
+		Autoplay:      s.Autoplay,
+		Mute:          s.Mute,
+		Pool:          s.Pool,
+		Stereo:        s.Stereo,
+		Pos:           s.Pos,
+		PannerOptions: s.Panner,
+	}
+	if s.Sprites != nil {
+		opts.Sprites = make(map[string]Sprite, len(s.Sprites))
+		for name, sprite := range s.Sprites {
+			opts.Sprites[name] = sprite.Sprite()
+		}
+	}
+	return opts
+}
+
+// ParseManifest decodes a JSON manifest. Unknown fields are rejected so that
+// typos don't go unnoticed.
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("howler: parsing manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// ParseManifestYAML decodes a YAML manifest. It accepts the same fields as
+// ParseManifest, and likewise rejects unknown ones.
+func ParseManifestYAML(r io.Reader) (*Manifest, error) {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("howler: parsing manifest: %w", err)
+	}
+	// Going through JSON reuses the JSON tags and decoders of the manifest's
+	// fields rather than duplicating them for YAML.
+	data, err := json.Marshal(yamlToJSON(doc))
+	if err != nil {
+		return nil, fmt.Errorf("howler: parsing manifest: %w", err)
+	}
+	return ParseManifest(bytes.NewReader(data))
+}
+
+// yamlToJSON converts the mappings in a decoded YAML document that have keys
+// other than strings, such as sprites named with numbers, into JSON objects.
+func yamlToJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = yamlToJSON(e)
+		}
+		return v
+	case map[any]any:
+		obj := make(map[string]any, len(v))
+		for k, e := range v {
+			obj[fmt.Sprint(k)] = yamlToJSON(e)
+		}
+		return obj
+	case []any:
+		for i, e := range v {
+			v[i] = yamlToJSON(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Names returns the names of the sounds in the manifest in sorted order.
+func (m *Manifest) Names() []string {
+	names := make([]string, 0, len(m.Sounds))
+	for name := range m.Sounds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options returns the options for every sound in the manifest, ready to be
+// passed to a Preloader.
+func (m *Manifest) Options() map[string]HowlOptions {
+	opts := make(map[string]HowlOptions, len(m.Sounds))
+	for name, sound := range m.Sounds {
+		opts[name] = sound.Options()
+	}
+	return opts
+}
+
+// Validate validates the options of every sound in the manifest, reporting
+// every problem found as a *ValidationError with fields such as
+// Sounds["laser"].Volume.
+func (m *Manifest) Validate() error {
+	var errs []FieldError
+	for _, name := range m.Names() {
+		err := m.Sounds[name].Options().Validate()
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			for _, e := range verr.Errors {
+				e.Field = fmt.Sprintf("Sounds[%q].%s", name, e.Field)
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Load validates the manifest and creates a Howl for each of its sounds. No
+// Howls are created if any sound is invalid.
+func (m *Manifest) Load() (*Registry, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Registry{
+		howls: make(map[string]Howl, len(m.Sounds)),
+		tags:  make(map[string][]string),
+	}
+	for _, name := range m.Names() {
+		sound := m.Sounds[name]
+		r.howls[name] = New(sound.Options())
+		for _, tag := range sound.Tags {
+			r.tags[tag] = append(r.tags[tag], name)
+		}
+	}
+	return r, nil
+}
+
+// LoadManifest parses a JSON manifest and creates a Howl for each of its
+// sounds.
+func LoadManifest(r io.Reader) (*Registry, error) {
+	m, err := ParseManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	return m.Load()
+}
+
+// LoadManifestYAML parses a YAML manifest and creates a Howl for each of its
+// sounds.
+func LoadManifestYAML(r io.Reader) (*Registry, error) {
+	m, err := ParseManifestYAML(r)
+	if err != nil {
+		return nil, err
+	}
+	return m.Load()
+}
+
+// Registry holds the Howls created from a Manifest, by name.
+type Registry struct {
+	howls map[string]Howl
+	tags  map[string][]string
+}
+
+// Get returns the Howl with the given name.
+func (r *Registry) Get(name string) (Howl, bool) {
+	h, ok := r.howls[name]
+	return h, ok
+}
+
+// Names returns the names of every Howl in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.howls))
+	for name := range r.howls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tagged returns the names of the Howls with the given tag in sorted order.
+func (r *Registry) Tagged(tag string) []string {
+	names := append([]string(nil), r.tags[tag]...)
+	sort.Strings(names)
+	return names
+}
+
+// Unload unloads every Howl in the registry.
+func (r *Registry) Unload() {
+	for _, h := range r.howls {
+		h.Unload()
+	}
+}
@@ -0,0 +1,143 @@
+package howler
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testManifestJSON = `{
+  "sounds": {
+    "laser": {
+      "src": ["sfx.webm", "sfx.mp3"],
+      "sprites": {"pew": {"offset": 0, "duration": 250}, "1": {"offset": 250, "duration": 100, "loop": true}},
+      "volume": 0.8,
+      "pos": [1, 2, 3],
+      "tags": ["sfx"]
+    },
+    "theme": {
+      "src": ["theme.mp3"],
+      "loop": true,
+      "tags": ["music"]
+    }
+  }
+}`
+
+const testManifestYAML = `
+sounds:
+  laser:
+    src: [sfx.webm, sfx.mp3]
+    sprites:
+      pew: {offset: 0, duration: 250}
+      1: {offset: 250, duration: 100, loop: true}
+    volume: 0.8
+    pos: [1, 2, 3]
+    tags: [sfx]
+  theme:
+    src: [theme.mp3]
+    loop: true
+    tags: [music]
+`
+
+func TestParseManifest(t *testing.T) {
+	want := &Manifest{Sounds: map[string]ManifestSound{
+		"laser": {
+			Sources: []string{"sfx.webm", "sfx.mp3"},
+			Sprites: map[string]ManifestSprite{
+				"pew": {Offset: 0, Duration: 250},
+				"1":   {Offset: 250, Duration: 100, Loop: true},
+			},
+			Volume: Some(0.8),
+			Pos:    Some(Vec3{1, 2, 3}),
+			Tags:   []string{"sfx"},
+		},
+		"theme": {
+			Sources: []string{"theme.mp3"},
+			Loop:    Some(true),
+			Tags:    []string{"music"},
+		},
+	}}
+
+	tests := []struct {
+		name  string
+		parse func(io.Reader) (*Manifest, error)
+		input string
+	}{
+		{"JSON", ParseManifest, testManifestJSON},
+		{"YAML", ParseManifestYAML, testManifestYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(m, want) {
+				t.Errorf("got %+v, want %+v", m, want)
+			}
+		})
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(io.Reader) (*Manifest, error)
+		input string
+	}{
+		{"JSON unknown field", ParseManifest, `{"sounds": {"a": {"src": ["a.mp3"], "volumme": 1}}}`},
+		{"JSON syntax", ParseManifest, `{"sounds": `},
+		{"YAML unknown field", ParseManifestYAML, "sounds:\n  a:\n    src: [a.mp3]\n    volumme: 1\n"},
+		{"YAML syntax", ParseManifestYAML, "sounds: [\n"},
+		{"YAML wrong type", ParseManifestYAML, "sounds:\n  a:\n    src: a.mp3\n"},
+		{"YAML short pos", ParseManifestYAML, "sounds:\n  a:\n    src: [a.mp3]\n    pos: [1, 2]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parse(strings.NewReader(tt.input)); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestManifestOptions(t *testing.T) {
+	m, err := ParseManifestYAML(strings.NewReader(testManifestYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := m.Options()["laser"]
+	if got, want := opts.Sprites["1"], (Sprite{Offset: 250 * time.Millisecond, Duration: 100 * time.Millisecond, Loop: true}); got != want {
+		t.Errorf("Sprites[\"1\"] = %+v, want %+v", got, want)
+	}
+	if opts.Volume.Or(1) != 0.8 {
+		t.Errorf("Volume = %v, want 0.8", opts.Volume)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	newTestBackend(t)
+	r, err := LoadManifestYAML(strings.NewReader(testManifestYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Unload()
+	if got, want := r.Names(), []string{"laser", "theme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+	if got, want := r.Tagged("music"), []string{"theme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tagged(music) = %q, want %q", got, want)
+	}
+	if h, ok := r.Get("laser"); !ok || !h.Valid() {
+		t.Error("Get(laser) found no valid Howl")
+	}
+
+	_, err = LoadManifest(strings.NewReader(`{"sounds": {"a": {"src": [], "volume": 2}}}`))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Errorf("LoadManifest of invalid sound = %v, want two field errors", err)
+	}
+}
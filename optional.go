@@ -0,0 +1,79 @@
+package howler
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is a value that may be left unset, in which case howler.js uses its
+// own default. The zero value is unset.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional set to value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+// Get returns the value and whether it has been set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// IsSet returns true if the value has been set.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Or returns the value if it has been set, otherwise def.
+func (o Optional[T]) Or(def T) T {
+	if o.set {
+		return o.value
+	}
+	return def
+}
+
+// IsZero returns true if the value hasn't been set, so that unset fields are
+// left out when encoding with the omitzero option.
+func (o Optional[T]) IsZero() bool {
+	return !o.set
+}
+
+// MarshalJSON encodes the value, or null if it hasn't been set.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes a value, leaving the Optional unset for null.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Optional[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.value); err != nil {
+		return err
+	}
+	o.set = true
+	return nil
+}
+
+// setOptional sets key on obj if o has been set.
+func setOptional[T any](obj Value, key string, o Optional[T]) {
+	if v, ok := o.Get(); ok {
+		obj.Set(key, v)
+	}
+}
+
+// anySlice converts s into a slice the backend can pass to JavaScript.
+func anySlice[T any](s []T) []any {
+	a := make([]any, len(s))
+	for i, v := range s {
+		a[i] = v
+	}
+	return a
+}
@@ -0,0 +1,61 @@
+package howler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOptionalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		volume Optional[float64]
+		loop   Optional[bool]
+		// json is the encoding of the two fields, left out when unset.
+		json string
+	}{
+		{name: "unset", json: ``},
+		{name: "zero", volume: Some(0.0), loop: Some(false), json: `"volume":0,"loop":false`},
+		{name: "value", volume: Some(0.5), loop: Some(true), json: `"volume":0.5,"loop":true`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := HowlOptions{Volume: tt.volume, Loop: tt.loop}
+			data, err := json.Marshal(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.json == "" {
+				if s := string(data); strings.Contains(s, `"volume"`) || strings.Contains(s, `"loop"`) {
+					t.Errorf("Marshal() = %s, want volume and loop left out", s)
+				}
+			} else if !strings.Contains(string(data), tt.json) {
+				t.Errorf("Marshal() = %s, want it to contain %s", data, tt.json)
+			}
+
+			var back HowlOptions
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			if back.Volume != tt.volume || back.Loop != tt.loop {
+				t.Errorf("round trip gave volume %+v, loop %+v; want %+v, %+v", back.Volume, back.Loop, tt.volume, tt.loop)
+			}
+		})
+	}
+}
+
+func TestOptionalNull(t *testing.T) {
+	o := Some(1.0)
+	if err := json.Unmarshal([]byte(" null "), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.IsSet() {
+		t.Errorf("Unmarshal(null) left %+v set", o)
+	}
+	if data, err := json.Marshal(o); err != nil || string(data) != "null" {
+		t.Errorf("Marshal() of an unset Optional = %s, %v; want null", data, err)
+	}
+	if got := o.Or(2); got != 2 {
+		t.Errorf("Or(2) = %v, want 2", got)
+	}
+}
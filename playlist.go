@@ -0,0 +1,637 @@
+package howler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Track is an entry of a Playlist.
+type Track struct {
+	Name    string
+	Options HowlOptions
+}
+
+// Tracks returns the sounds with the given names as playlist tracks, in the
+// same order.
+func (m *Manifest) Tracks(names ...string) ([]Track, error) {
+	tracks := make([]Track, len(names))
+	for i, name := range names {
+		sound, ok := m.Sounds[name]
+		if !ok {
+			return nil, fmt.Errorf("howler: manifest has no sound %q", name)
+		}
+		tracks[i] = Track{Name: name, Options: sound.Options()}
+	}
+	return tracks, nil
+}
+
+// Repeat sets what a Playlist does when a track ends.
+type Repeat int
+
+const (
+	// RepeatOff plays each track once, stopping after the last.
+	RepeatOff Repeat = iota
+	// RepeatOne loops the current track.
+	RepeatOne
+	// RepeatAll starts again from the first track after the last.
+	RepeatAll
+)
+
+func (r Repeat) String() string {
+	switch r {
+	case RepeatOff:
+		return "off"
+	case RepeatOne:
+		return "one"
+	case RepeatAll:
+		return "all"
+	default:
+		return fmt.Sprintf("Repeat(%d)", int(r))
+	}
+}
+
+// PlaylistOptions configures a Playlist.
+type PlaylistOptions struct {
+	// Repeat sets what happens when a track ends.
+	Repeat Repeat
+
+	// Shuffle plays the tracks in a random order, shuffled again each time
+	// the playlist repeats.
+	Shuffle bool
+
+	// Crossfade is how long to crossfade from one track to the next. Zero
+	// starts each track as soon as the one before it ends. A track that
+	// follows itself, as a single track does with RepeatAll, starts again
+	// without crossfading.
+	Crossfade time.Duration
+
+	// Curve is the curve crossfades follow. See Crossfade.
+	Curve FadeCurve
+
+	// OnTrackChange is called each time a different track starts, with its
+	// index in the list of tracks the playlist was created with.
+	OnTrackChange func(index int, track Track)
+
+	// OnError is called when a track fails to load. A track that fails while
+	// it is loaded ahead of time is loaded again once its turn comes, and the
+	// playlist moves on to the next track if it fails while current.
+	OnError func(index int, track Track, err error)
+
+	// Rand is the source the tracks are shuffled with. It must not be used by
+	// anything else while the playlist is. If nil, a source seeded from the
+	// current time is used.
+	Rand *rand.Rand
+}
+
+// Playlist plays a list of tracks one after another. Tracks are created with
+// Preload set to false, and only the current and upcoming tracks are kept
+// loaded.
+type Playlist struct {
+	backend Backend
+	tracks  []Track
+	opts    PlaylistOptions
+	rand    *rand.Rand
+
+	mu    sync.Mutex
+	howls map[int]Howl
+	// failures holds the tracks that failed to load before their turn came.
+	failures map[int]bool
+	order    []int
+	// upcoming is the order to use once a shuffled playlist repeats.
+	upcoming []int
+	pos      int
+	// sound is the sound of the current track, or nil if it hasn't started.
+	sound Sound
+	// gen is increased whenever the current sound changes, so stale
+	// crossfade timers can tell they are no longer needed.
+	gen   int
+	timer Timer
+}
+
+// NewPlaylist creates a playlist of tracks and starts loading the first.
+func NewPlaylist(tracks []Track, opts PlaylistOptions) *Playlist {
+	p := &Playlist{
+		backend:  backend,
+		tracks:   tracks,
+		opts:     opts,
+		rand:     randOrNew(opts.Rand),
+		howls:    make(map[int]Howl),
+		failures: make(map[int]bool),
+		order:    make([]int, len(tracks)),
+	}
+	for i := range p.order {
+		p.order[i] = i
+	}
+	if opts.Shuffle {
+		shuffle(p.rand, p.order)
+	}
+	p.mu.Lock()
+	p.prune()
+	p.mu.Unlock()
+	return p
+}
+
+// Play starts or resumes the current track.
+func (p *Playlist) Play() {
+	p.mu.Lock()
+	var notify func()
+	switch {
+	case len(p.order) == 0:
+	case p.sound == nil:
+		notify = p.start(p.pos, false)
+	case !p.sound.Playing():
+		// Keep hold of the sound played, as playing a whole Howl starts a
+		// new one.
+		if s, err := p.sound.TryPlay(); err == nil {
+			p.sound = s
+		}
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// Pause pauses the current track.
+func (p *Playlist) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancel()
+	if p.sound != nil {
+		p.sound.Pause()
+	}
+}
+
+// Stop stops the current track. Play starts it again from the beginning.
+func (p *Playlist) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancel()
+	p.gen++
+	if p.sound != nil {
+		p.sound.Stop()
+		p.sound = nil
+	}
+}
+
+// Next moves on to the next track and plays it, returning false if the
+// current track is the last and the playlist doesn't repeat.
+func (p *Playlist) Next() bool {
+	p.mu.Lock()
+	pos, ok := p.next(false)
+	var notify func()
+	if ok {
+		notify = p.start(pos, true)
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+	return ok
+}
+
+// Previous moves back to the previous track and plays it, returning false if
+// the current track is the first and the playlist doesn't repeat.
+func (p *Playlist) Previous() bool {
+	p.mu.Lock()
+	pos, ok := p.pos-1, p.pos > 0
+	if !ok && p.opts.Repeat == RepeatAll && len(p.order) > 0 {
+		pos, ok = len(p.order)-1, true
+	}
+	var notify func()
+	if ok {
+		notify = p.start(pos, true)
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+	return ok
+}
+
+// PlayTrack plays the track with the given index in the list of tracks the
+// playlist was created with.
+func (p *Playlist) PlayTrack(index int) {
+	p.mu.Lock()
+	var notify func()
+	for pos, i := range p.order {
+		if i == index {
+			notify = p.start(pos, true)
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// Current returns the index and track that is playing or will play next.
+func (p *Playlist) Current() (int, Track) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.order) == 0 {
+		return -1, Track{}
+	}
+	i := p.order[p.pos]
+	return i, p.tracks[i]
+}
+
+// Sound returns the sound of the current track, or nil if it hasn't started.
+func (p *Playlist) Sound() Sound {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sound
+}
+
+// Repeat returns what happens when a track ends.
+func (p *Playlist) Repeat() Repeat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.opts.Repeat
+}
+
+// SetRepeat sets what happens when a track ends.
+func (p *Playlist) SetRepeat(repeat Repeat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.opts.Repeat = repeat
+	p.upcoming = nil
+	if len(p.order) > 0 {
+		p.howl(p.order[p.pos]).SetLoop(repeat == RepeatOne)
+	}
+	p.schedule()
+	p.prune()
+}
+
+// Shuffle returns true if the tracks are played in a random order.
+func (p *Playlist) Shuffle() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.opts.Shuffle
+}
+
+// SetShuffle sets whether the tracks are played in a random order. The
+// current track carries on playing either way.
+func (p *Playlist) SetShuffle(shuffled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if shuffled == p.opts.Shuffle || len(p.order) == 0 {
+		p.opts.Shuffle = shuffled
+		return
+	}
+	p.opts.Shuffle = shuffled
+	p.upcoming = nil
+
+	current := p.order[p.pos]
+	for i := range p.order {
+		p.order[i] = i
+	}
+	if shuffled {
+		// Keep the current track first so that every other track still
+		// plays before the playlist repeats.
+		p.order[0], p.order[current] = current, 0
+		shuffle(p.rand, p.order[1:])
+		p.pos = 0
+	} else {
+		p.pos = current
+	}
+	p.prune()
+}
+
+// Unload stops the playlist and unloads every track.
+func (p *Playlist) Unload() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancel()
+	p.gen++
+	p.sound = nil
+	for i, h := range p.howls {
+		h.Unload()
+		delete(p.howls, i)
+		delete(p.failures, i)
+	}
+}
+
+// start switches to the track at pos in the play order and plays it,
+// crossfading from the current track if fade is true and it is playing. It
+// returns the function to call once p.mu is released.
+func (p *Playlist) start(pos int, fade bool) func() {
+	index := p.order[pos]
+	h := p.howl(index)
+	h.SetLoop(p.opts.Repeat == RepeatOne)
+	if p.failures[index] {
+		// The track failed to load ahead of time, so try again now that it
+		// is needed.
+		delete(p.failures, index)
+		h.Load()
+	}
+
+	p.cancel()
+	p.gen++
+
+	from := p.sound
+	var played Sound
+	if fade && from != nil && from.Playing() && p.opts.Crossfade > 0 && !sameHowl(from, h) {
+		played, _ = startCrossfade(from, h, p.opts.Crossfade, p.opts.Curve, func() {
+			// Unload the outgoing track once it has faded out, outside of
+			// whatever call finished the fade.
+			p.backend.AfterFunc(0, func() {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				if p.sound != nil {
+					p.prune()
+				}
+			})
+		})
+		if played == nil {
+			// The track was still fading out from an earlier crossfade, so it
+			// carries on with the sound it was already playing.
+			played = playingSound(h)
+		}
+	} else {
+		if from != nil {
+			from.Stop()
+		}
+		played = h.Play()
+	}
+
+	p.sound = played
+	p.pos = pos
+	p.prune()
+
+	track := p.tracks[index]
+	return func() {
+		if p.opts.OnTrackChange != nil {
+			p.opts.OnTrackChange(index, track)
+		}
+	}
+}
+
+// next returns the position in the play order of the track after the current
+// one. When auto is true the current track has ended by itself.
+func (p *Playlist) next(auto bool) (int, bool) {
+	switch {
+	case len(p.order) == 0:
+		return 0, false
+	case auto && p.opts.Repeat == RepeatOne:
+		return p.pos, true
+	case p.pos+1 < len(p.order):
+		return p.pos + 1, true
+	case p.opts.Repeat == RepeatAll:
+		if p.opts.Shuffle {
+			p.order = p.reshuffled()
+			p.upcoming = nil
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// peek returns the index of the track that will play after the current one
+// ends, without changing the play order.
+func (p *Playlist) peek() (int, bool) {
+	switch {
+	case len(p.order) == 0:
+		return 0, false
+	case p.opts.Repeat == RepeatOne:
+		return p.order[p.pos], true
+	case p.pos+1 < len(p.order):
+		return p.order[p.pos+1], true
+	case p.opts.Repeat == RepeatAll:
+		if p.opts.Shuffle {
+			return p.reshuffled()[0], true
+		}
+		return p.order[0], true
+	default:
+		return 0, false
+	}
+}
+
+// reshuffled returns the play order to use once a shuffled playlist repeats,
+// choosing it the first time it is needed.
+func (p *Playlist) reshuffled() []int {
+	if p.upcoming == nil {
+		p.upcoming = append([]int(nil), p.order...)
+		shuffle(p.rand, p.upcoming)
+		// Don't play the last track twice in a row.
+		if n := len(p.upcoming); n > 1 && p.upcoming[0] == p.order[n-1] {
+			p.upcoming[0], p.upcoming[n-1] = p.upcoming[n-1], p.upcoming[0]
+		}
+	}
+	return p.upcoming
+}
+
+// howl returns the Howl for the track with the given index, creating it if
+// needed.
+func (p *Playlist) howl(index int) Howl {
+	if h, ok := p.howls[index]; ok {
+		return h
+	}
+
+	track := p.tracks[index]
+	opts := track.Options
+	opts.Preload = Some(false)
+	opts.Autoplay = Some(false)
+	onLoadError := opts.OnLoadError
+	opts.OnLoadError = func(err error) {
+		if onLoadError != nil {
+			onLoadError(err)
+		}
+		p.failed(index, err)
+	}
+
+	h := New(opts)
+	h.On(EventPlay, func(s Sound) { p.changed(s) })
+	h.On(EventSeek, func(s Sound) { p.changed(s) })
+	h.On(EventPause, func(s Sound) { p.changed(s) })
+	h.On(EventStop, func(s Sound) { p.changed(s) })
+	h.On(EventEnd, func(s Sound) { p.ended(s) })
+	p.howls[index] = h
+	return h
+}
+
+// prune loads the current and upcoming tracks, and unloads every other track
+// that isn't still fading out.
+func (p *Playlist) prune() {
+	if len(p.order) == 0 {
+		return
+	}
+	keep := map[int]bool{p.order[p.pos]: true}
+	if next, ok := p.peek(); ok {
+		keep[next] = true
+	}
+
+	for i, h := range p.howls {
+		if !keep[i] && !h.Playing() {
+			// Unload once the events the track has already fired, such as
+			// stop, have been delivered to its listeners.
+			p.backend.AfterFunc(0, h.Unload)
+			delete(p.howls, i)
+			delete(p.failures, i)
+		}
+	}
+	for i := range keep {
+		if h := p.howl(i); h.State() == StateUnloaded {
+			h.Load()
+		}
+	}
+}
+
+// cancel stops the timer waiting to move on to the next track.
+func (p *Playlist) cancel() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+// schedule starts the timer that moves on to the next track, either to
+// crossfade into it before the current one ends or to start it as soon as the
+// current one ends rather than waiting for the end event to be delivered.
+func (p *Playlist) schedule() {
+	p.cancel()
+	if p.sound == nil || p.opts.Repeat == RepeatOne || !p.sound.Playing() {
+		return
+	}
+	next, ok := p.peek()
+	if !ok {
+		return
+	}
+	fade := p.opts.Crossfade
+	if next == p.order[p.pos] {
+		// The track starts again from the beginning rather than fading.
+		fade = 0
+	}
+
+	rate := p.sound.Rate()
+	if rate <= 0 {
+		return
+	}
+	remaining := time.Duration(float64(p.sound.Duration()-p.sound.Seek())/rate) - fade
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	gen := p.gen
+	p.timer = p.backend.AfterFunc(remaining, func() {
+		p.mu.Lock()
+		var notify func()
+		if gen == p.gen {
+			p.timer = nil
+			if pos, ok := p.next(true); ok {
+				notify = p.start(pos, true)
+			}
+		}
+		p.mu.Unlock()
+
+		if notify != nil {
+			notify()
+		}
+	})
+}
+
+// changed handles the current sound starting, stopping or seeking.
+func (p *Playlist) changed(s Sound) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.isCurrent(s) {
+		p.schedule()
+	}
+}
+
+// ended moves on to the next track when the current one ends.
+func (p *Playlist) ended(s Sound) {
+	p.mu.Lock()
+	var notify func()
+	if p.isCurrent(s) && p.opts.Repeat != RepeatOne {
+		if pos, ok := p.next(true); ok {
+			notify = p.start(pos, false)
+		} else {
+			p.cancel()
+			p.gen++
+			p.sound = nil
+			p.pos = 0
+			p.prune()
+		}
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// failed reports a track that failed to load, skipping past it if it is the
+// current one and otherwise loading it again once it is needed.
+func (p *Playlist) failed(index int, err error) {
+	if p.opts.OnError != nil {
+		p.opts.OnError(index, p.tracks[index], err)
+	}
+
+	p.mu.Lock()
+	var notify func()
+	if p.sound != nil && len(p.order) > 0 && p.order[p.pos] == index {
+		p.sound = nil
+		if pos, ok := p.next(true); ok && p.order[pos] != index {
+			notify = p.start(pos, false)
+		}
+	} else if _, ok := p.howls[index]; ok {
+		p.failures[index] = true
+	}
+	p.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// isCurrent returns true if s is the sound of the current track.
+func (p *Playlist) isCurrent(s Sound) bool {
+	if p.sound == nil {
+		return false
+	}
+	a, ok := keyOf(s).(soundKey)
+	b, current := keyOf(p.sound).(soundKey)
+	return ok && current && a == b
+}
+
+// sameHowl returns true if s is h or one of its sounds.
+func sameHowl(s Sound, h Howl) bool {
+	key, ok := keyOf(s).(soundKey)
+	return ok && key.states == h.states
+}
+
+// playingSound returns a sound h is playing, or h itself if it isn't playing
+// any.
+func playingSound(h Howl) Sound {
+	for _, s := range h.Sounds() {
+		if s.Playing() {
+			return s
+		}
+	}
+	return h
+}
+
+// shuffle shuffles order using r.
+func shuffle(r *rand.Rand, order []int) {
+	r.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+}
+
+// randOrNew returns r, or a new source seeded from the current time if r is
+// nil.
+func randOrNew(r *rand.Rand) *rand.Rand {
+	if r != nil {
+		return r
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
@@ -0,0 +1,293 @@
+package howler
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// testTracks returns tracks named after their sources, each a second long.
+func testTracks(b *FakeBackend, names ...string) []Track {
+	tracks := make([]Track, len(names))
+	for i, name := range names {
+		b.SetDuration(name, time.Second)
+		tracks[i] = Track{Name: name, Options: HowlOptions{Source: []string{name}}}
+	}
+	return tracks
+}
+
+func TestPlaylistRepeat(t *testing.T) {
+	tests := []struct {
+		repeat Repeat
+		want   []int
+	}{
+		{RepeatOff, []int{0, 1, 2}},
+		{RepeatOne, []int{0}},
+		{RepeatAll, []int{0, 1, 2, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repeat.String(), func(t *testing.T) {
+			b := newTestBackend(t)
+			var changes []int
+			p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3", "c.mp3"), PlaylistOptions{
+				Repeat:        tt.repeat,
+				OnTrackChange: func(index int, _ Track) { changes = append(changes, index) },
+			})
+			defer p.Unload()
+
+			p.Play()
+			for i := 0; i < 5; i++ {
+				b.Advance(time.Second)
+			}
+			if !equalInts(changes, tt.want) {
+				t.Errorf("tracks played = %v, want %v", changes, tt.want)
+			}
+			if playing := p.Sound() != nil && p.Sound().Playing(); playing != (tt.repeat != RepeatOff) {
+				t.Errorf("playing = %v after five seconds", playing)
+			}
+		})
+	}
+}
+
+func TestPlaylistNavigation(t *testing.T) {
+	b := newTestBackend(t)
+	p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3", "c.mp3"), PlaylistOptions{})
+	defer p.Unload()
+
+	p.Play()
+	b.Flush()
+	steps := []struct {
+		name string
+		move func() bool
+		ok   bool
+		want int
+	}{
+		{"next", p.Next, true, 1},
+		{"next again", p.Next, true, 2},
+		{"past the end", p.Next, false, 2},
+		{"previous", p.Previous, true, 1},
+		{"track", func() bool { p.PlayTrack(0); return true }, true, 0},
+		{"before the start", p.Previous, false, 0},
+	}
+	for _, step := range steps {
+		ok := step.move()
+		b.Flush()
+		if index, _ := p.Current(); ok != step.ok || index != step.want {
+			t.Errorf("%s: ok, current = %v, %d; want %v, %d", step.name, ok, index, step.ok, step.want)
+		}
+		if !p.Sound().Playing() {
+			t.Errorf("%s: current track isn't playing", step.name)
+		}
+	}
+}
+
+func TestPlaylistShuffle(t *testing.T) {
+	b := newTestBackend(t)
+	names := []string{"a.mp3", "b.mp3", "c.mp3", "d.mp3", "e.mp3"}
+	order := func(seed int64) []int {
+		var changes []int
+		p := NewPlaylist(testTracks(b, names...), PlaylistOptions{
+			Repeat:        RepeatAll,
+			Shuffle:       true,
+			Rand:          rand.New(rand.NewSource(seed)),
+			OnTrackChange: func(index int, _ Track) { changes = append(changes, index) },
+		})
+		defer p.Unload()
+		p.Play()
+		for i := 0; i < 2*len(names); i++ {
+			b.Advance(time.Second)
+		}
+		return changes[:2*len(names)]
+	}
+
+	first := order(1)
+	if again := order(1); !equalInts(first, again) {
+		t.Errorf("orders with the same seed differ: %v and %v", first, again)
+	}
+	// Every track plays once before the playlist repeats, and no track plays
+	// twice in a row.
+	for _, cycle := range [][]int{first[:len(names)], first[len(names):]} {
+		sorted := append([]int(nil), cycle...)
+		sort.Ints(sorted)
+		if !equalInts(sorted, []int{0, 1, 2, 3, 4}) {
+			t.Errorf("cycle %v doesn't play every track once", cycle)
+		}
+	}
+	for i := 1; i < len(first); i++ {
+		if first[i] == first[i-1] {
+			t.Errorf("track %d played twice in a row in %v", first[i], first)
+		}
+	}
+}
+
+func TestPlaylistCrossfade(t *testing.T) {
+	b := newTestBackend(t)
+	p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3"), PlaylistOptions{Crossfade: 200 * time.Millisecond})
+	defer p.Unload()
+
+	p.Play()
+	b.Flush()
+	first := p.Sound()
+	b.Advance(900 * time.Millisecond)
+	if index, _ := p.Current(); index != 1 {
+		t.Fatalf("current = %d during the crossfade, want 1", index)
+	}
+	if !first.Playing() || !p.Sound().Playing() {
+		t.Error("both tracks aren't playing during the crossfade")
+	}
+	b.Advance(200 * time.Millisecond)
+	if first.Playing() {
+		t.Error("first track still playing after the crossfade")
+	}
+}
+
+func TestPlaylistError(t *testing.T) {
+	b := newTestBackend(t)
+	b.FailLoad("a.mp3", MediaErrorNetwork)
+	var failed, changes []int
+	p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3", "c.mp3"), PlaylistOptions{
+		OnTrackChange: func(index int, _ Track) { changes = append(changes, index) },
+		OnError:       func(index int, _ Track, _ error) { failed = append(failed, index) },
+	})
+	defer p.Unload()
+
+	p.Play()
+	for i := 0; i < 3; i++ {
+		b.Advance(time.Second)
+	}
+	// Once stopped the playlist loads the first track again, ready to be
+	// played, so it may have failed more than once.
+	if len(failed) == 0 || failed[0] != 0 {
+		t.Errorf("failed = %v, want track 0 to fail", failed)
+	}
+	if !equalInts(changes, []int{0, 1, 2}) {
+		t.Errorf("tracks played = %v, want [0 1 2]", changes)
+	}
+}
+
+func TestPlaylistPreloadError(t *testing.T) {
+	tests := []struct {
+		name string
+		// recover is true if the track loads when tried again.
+		recover bool
+		want    []int
+	}{
+		{"retried", true, []int{0, 1, 2}},
+		{"skipped", false, []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.FailLoad("b.mp3", MediaErrorNetwork)
+			var failed, changes []int
+			p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3", "c.mp3"), PlaylistOptions{
+				OnTrackChange: func(index int, _ Track) { changes = append(changes, index) },
+				OnError:       func(index int, _ Track, _ error) { failed = append(failed, index) },
+			})
+			defer p.Unload()
+
+			p.Play()
+			b.Advance(500 * time.Millisecond)
+			if !equalInts(failed, []int{1}) {
+				t.Fatalf("failed = %v while preloading, want [1]", failed)
+			}
+			if tt.recover {
+				b.FailLoad("b.mp3", 0)
+			}
+			b.Advance(time.Second)
+			if index, _ := p.Current(); tt.recover && (index != 1 || !p.Sound().Playing()) {
+				t.Errorf("current = %d after retrying, want 1 playing", index)
+			}
+			b.Advance(time.Second)
+			if !equalInts(changes, tt.want) {
+				t.Errorf("tracks played = %v, want %v", changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistSingleTrack(t *testing.T) {
+	b := newTestBackend(t)
+	var changes []int
+	p := NewPlaylist(testTracks(b, "a.mp3"), PlaylistOptions{
+		Repeat:        RepeatAll,
+		Crossfade:     200 * time.Millisecond,
+		OnTrackChange: func(index int, _ Track) { changes = append(changes, index) },
+	})
+	defer p.Unload()
+
+	p.Play()
+	for _, at := range []time.Duration{900 * time.Millisecond, 1100 * time.Millisecond} {
+		b.Advance(at - b.Now())
+		s := p.Sound()
+		if !s.Playing() || s.Volume() != 1 {
+			t.Errorf("at %v: playing, volume = %v, %v; want true, 1", at, s.Playing(), s.Volume())
+		}
+	}
+	if got := p.Sound().Seek(); got != 100*time.Millisecond {
+		t.Errorf("Seek() = %v after repeating, want 100ms", got)
+	}
+	if !equalInts(changes, []int{0, 0}) {
+		t.Errorf("tracks played = %v, want [0 0]", changes)
+	}
+}
+
+func TestPlaylistGapless(t *testing.T) {
+	b := newTestBackend(t)
+	p := NewPlaylist(testTracks(b, "a.mp3", "b.mp3"), PlaylistOptions{})
+	defer p.Unload()
+
+	p.Play()
+	b.Flush()
+	// The next track is started by a timer at the end of the current one,
+	// rather than by its end event, which arrives later in the browser.
+	p.mu.Lock()
+	scheduled := p.timer != nil
+	p.mu.Unlock()
+	if !scheduled {
+		t.Fatal("no timer to start the next track")
+	}
+	b.Advance(time.Second)
+	if index, _ := p.Current(); index != 1 || p.Sound().Seek() != 0 || !p.Sound().Playing() {
+		t.Errorf("current, seek = %d, %v at the end of the first track; want 1, 0s", index, p.Sound().Seek())
+	}
+}
+
+func TestPlaylistResume(t *testing.T) {
+	b := newTestBackend(t)
+	p := NewPlaylist(testTracks(b, "a.mp3"), PlaylistOptions{})
+	defer p.Unload()
+
+	p.Play()
+	b.Flush()
+	// Stand in for a track that was still fading out, which the playlist
+	// holds as the whole Howl.
+	h := p.howls[0]
+	p.mu.Lock()
+	p.sound = h
+	p.mu.Unlock()
+	h.Stop()
+	b.Flush()
+
+	p.Play()
+	b.Flush()
+	if _, ok := p.Sound().(soundSpecific); !ok {
+		t.Fatalf("Sound() = %T after Play, want the sound played", p.Sound())
+	}
+	if n := len(h.Sounds()); n != 1 {
+		t.Errorf("Howl has %d sounds, want 1", n)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
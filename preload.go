@@ -0,0 +1,139 @@
+package howler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Progress reports how far a Preloader has got, counted in Howls. It has no
+// byte counts because howler.js fetches audio itself, through XHR or an Audio
+// element, and reports no progress while doing so.
+type Progress struct {
+	// Name is the Howl that just finished loading or failed.
+	Name string
+	// Err is the error it failed with, or nil if it loaded.
+	Err error
+
+	Loaded int
+	Failed int
+	Total  int
+}
+
+// Done returns true once every Howl has either loaded or failed.
+func (p Progress) Done() bool {
+	return p.Loaded+p.Failed == p.Total
+}
+
+// PreloadError lists the Howls a Preloader failed to load, keyed by name.
+type PreloadError struct {
+	Errors map[string]error
+}
+
+func (e *PreloadError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + e.Errors[name].Error()
+	}
+	return fmt.Sprintf("howler: failed to load %d sounds: %s", len(names), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of every Howl that failed to load.
+func (e *PreloadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Preloader creates and loads many Howls at once, such as every sound used by
+// a scene.
+type Preloader struct {
+	// Concurrency limits how many Howls load at the same time. Zero means no
+	// limit.
+	Concurrency int
+
+	// OnProgress is called each time a Howl loads or fails. Calls are never made
+	// concurrently.
+	OnProgress func(Progress)
+}
+
+// Load creates a Howl for each entry of opts and waits for them all to load.
+// It returns the Howls that loaded keyed by the same names, and a
+// *PreloadError if any failed. Howls that fail to load are unloaded. If ctx is
+// done first, the Howls still loading fail with ctx's error.
+//
+// Like Wait, Load can only return once the events reporting each load have
+// run. With a FakeBackend that means Flush or Advance must be called from
+// another goroutine while Load is waiting.
+func (p *Preloader) Load(ctx context.Context, opts map[string]HowlOptions) (map[string]Howl, error) {
+	names := make([]string, 0, len(opts))
+	for name := range opts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type result struct {
+		name string
+		howl Howl
+		err  error
+	}
+
+	workers := p.Concurrency
+	if workers <= 0 || workers > len(names) {
+		workers = len(names)
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for name := range jobs {
+				if err := ctx.Err(); err != nil {
+					results <- result{name: name, err: err}
+					continue
+				}
+				howl, err := NewAndLoad(ctx, opts[name])
+				results <- result{name, howl, err}
+			}
+		}()
+	}
+	go func() {
+		for _, name := range names {
+			jobs <- name
+		}
+		close(jobs)
+	}()
+
+	howls := make(map[string]Howl, len(names))
+	failures := make(map[string]error)
+	progress := Progress{Total: len(names)}
+	for range names {
+		r := <-results
+		if r.err != nil {
+			failures[r.name] = r.err
+			progress.Failed++
+		} else {
+			howls[r.name] = r.howl
+			progress.Loaded++
+		}
+		progress.Name = r.name
+		progress.Err = r.err
+		if p.OnProgress != nil {
+			p.OnProgress(progress)
+		}
+	}
+
+	if len(failures) > 0 {
+		return howls, &PreloadError{Errors: failures}
+	}
+	return howls, nil
+}
@@ -0,0 +1,73 @@
+package howler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPreloader(t *testing.T) {
+	b := newTestBackend(t)
+	b.FailLoad("bad.mp3", MediaErrorDecode)
+
+	// Run the load events while Load waits for them.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				b.Flush()
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	var progress []Progress
+	p := Preloader{
+		Concurrency: 2,
+		OnProgress:  func(pr Progress) { progress = append(progress, pr) },
+	}
+	howls, err := p.Load(context.Background(), map[string]HowlOptions{
+		"a":   {Source: []string{"a.mp3"}},
+		"b":   {Source: []string{"b.mp3"}},
+		"bad": {Source: []string{"bad.mp3"}},
+	})
+
+	var perr *PreloadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Load() = %v, want a *PreloadError", err)
+	}
+	var lerr *LoadError
+	if _, ok := perr.Errors["bad"]; !ok || len(perr.Errors) != 1 || !errors.As(err, &lerr) {
+		t.Errorf("Errors = %v, want a LoadError for bad", perr.Errors)
+	}
+	if len(howls) != 2 || !howls["a"].Valid() || !howls["b"].Valid() {
+		t.Errorf("howls = %v, want a and b", howls)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("OnProgress called %d times, want 3", len(progress))
+	}
+	last := progress[2]
+	if !last.Done() || last.Loaded != 2 || last.Failed != 1 || last.Total != 3 {
+		t.Errorf("last Progress = %+v", last)
+	}
+}
+
+func TestPreloaderCanceled(t *testing.T) {
+	newTestBackend(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var p Preloader
+	howls, err := p.Load(ctx, map[string]HowlOptions{"a": {Source: []string{"a.mp3"}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() = %v, want %v", err, context.Canceled)
+	}
+	if len(howls) != 0 {
+		t.Errorf("howls = %v, want none", howls)
+	}
+}
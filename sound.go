@@ -1,17 +1,32 @@
 package howler
 
 import (
-	"syscall/js"
+	"fmt"
+	"strconv"
 	"time"
 )
 
-// State describes the load status of a given Howl.
+// State describes the load status of a given Howl, or where a single sound is
+// in its lifecycle.
 type State int
 
 const (
 	StateUnloaded State = iota
 	StateLoading
 	StateLoaded
+
+	// StatePlaying is a sound that is playing.
+	StatePlaying
+	// StatePaused is a sound paused part way through.
+	StatePaused
+	// StateStopped is a sound that was stopped and will start from the
+	// beginning if played again.
+	StateStopped
+	// StateEnded is a sound that played to the end of its sprite.
+	StateEnded
+	// StateRecycled is a sound howler.js has drained from its pool or reused
+	// for another sound. It can't be played again.
+	StateRecycled
 )
 
 var states = map[string]State{
@@ -20,17 +35,60 @@ var states = map[string]State{
 	"loaded":   StateLoaded,
 }
 
+func (s State) String() string {
+	switch s {
+	case StateUnloaded:
+		return "unloaded"
+	case StateLoading:
+		return "loading"
+	case StateLoaded:
+		return "loaded"
+	case StatePlaying:
+		return "playing"
+	case StatePaused:
+		return "paused"
+	case StateStopped:
+		return "stopped"
+	case StateEnded:
+		return "ended"
+	case StateRecycled:
+		return "recycled"
+	default:
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Sound is either a Howl, controlling every sound it is playing, or a single
+// sound played by one.
+//
+// Play returns an invalid Sound when a sound couldn't be played. Calling its
+// methods is safe: they do nothing and report zero values, and Err says why it
+// is invalid.
 type Sound interface {
 	ID() int
+	// Valid returns false if the Sound doesn't refer to a Howl or sound.
+	Valid() bool
+	// Err returns why the Sound is invalid, or nil if it is valid.
+	Err() error
 	State() State
 	Playing() bool
 	Duration() time.Duration
 	Play() Sound
+	// TryPlay is like Play, but also returns the error when the sound couldn't
+	// be played. Failures howler.js only reports later, such as audio being
+	// locked, are reported through OnPlayError instead.
+	TryPlay() (Sound, error)
 	Pause()
 	Stop()
 	Mute()
 	Unmute()
-	Fade(from float64, to float64, duration time.Duration)
+	// Fade fades between two volumes, returning a channel that is closed once
+	// the fade finishes or is interrupted by another fade, a change of volume
+	// or the sound stopping.
+	Fade(from float64, to float64, duration time.Duration) <-chan struct{}
+	// FadeWith is like Fade, but follows curve instead of changing the volume
+	// at a constant rate.
+	FadeWith(from float64, to float64, duration time.Duration, curve FadeCurve) <-chan struct{}
 	Volume() float64
 	SetVolume(volume float64)
 	Rate() float64
@@ -43,20 +101,33 @@ type Sound interface {
 	SetStereo(stereo float64)
 	Pos() (x, y, z float64)
 	SetPos(x, y, z float64)
+	PosVec() Vec3
+	SetPosVec(pos Vec3)
 	Orientation() (x, y, z float64)
 	SetOrientation(x, y, z float64)
+	OrientationVec() Vec3
+	SetOrientationVec(orientation Vec3)
 	PannerAttr() PannerAttr
 	SetPannerAttr(attr PannerAttr)
 }
 
 type soundGroup struct {
-	value js.Value
+	value  Value
+	states *soundStates
 }
 
 func (g soundGroup) ID() int {
 	return -1
 }
 
+func (g soundGroup) Valid() bool {
+	return g.value != nil
+}
+
+func (g soundGroup) Err() error {
+	return nil
+}
+
 func (g soundGroup) State() State {
 	return states[g.value.Call("state").String()]
 }
@@ -70,13 +141,25 @@ func (g soundGroup) Duration() time.Duration {
 }
 
 func (g soundGroup) Play() Sound {
-	if result := g.value.Call("play"); result.Truthy() {
-		return soundSpecific{
-			id:    result.Int(),
-			value: g.value,
-		}
+	sound, _ := g.TryPlay()
+	return sound
+}
+
+func (g soundGroup) TryPlay() (Sound, error) {
+	id, err := g.states.play(g.value, -1, func() int {
+		return playedID(g.value.Call("play"))
+	})
+	if err != nil {
+		return invalid(err)
 	}
-	return soundGroup{}
+	if id < 0 {
+		return invalid(ErrNotPlayed)
+	}
+	return soundSpecific{
+		id:     id,
+		value:  g.value,
+		states: g.states,
+	}, nil
 }
 
 func (g soundGroup) Pause() {
@@ -84,6 +167,7 @@ func (g soundGroup) Pause() {
 }
 
 func (g soundGroup) Stop() {
+	g.states.interrupt(g.value, -1)
 	g.value.Call("stop")
 }
 
@@ -95,16 +179,25 @@ func (g soundGroup) Unmute() {
 	g.value.Call("mute", false)
 }
 
-func (g soundGroup) Fade(from float64, to float64, duration time.Duration) {
-	g.value.Call("fade", from, to, duration.Milliseconds())
+func (g soundGroup) Fade(from float64, to float64, duration time.Duration) <-chan struct{} {
+	return g.FadeWith(from, to, duration, FadeLinear)
+}
+
+func (g soundGroup) FadeWith(from float64, to float64, duration time.Duration, curve FadeCurve) <-chan struct{} {
+	return g.fadeThen(from, to, duration, curve, nil)
+}
+
+func (g soundGroup) fadeThen(from, to float64, duration time.Duration, curve FadeCurve, then func()) <-chan struct{} {
+	return g.states.fade(g.value, -1, from, to, duration, curve, then)
 }
 
 func (g soundGroup) Volume() float64 {
-	return g.value.Call("volume").Float()
+	return g.states.getVolume(g.value, -1)
 }
 
 func (g soundGroup) SetVolume(volume float64) {
-	g.value.Call("volume", volume)
+	g.states.interrupt(g.value, -1)
+	g.states.setVolume(g.value, -1, volume, false)
 }
 
 func (g soundGroup) Rate() float64 {
@@ -140,29 +233,39 @@ func (g soundGroup) SetStereo(stereo float64) {
 }
 
 func (g soundGroup) Pos() (x, y, z float64) {
-	pos := g.value.Call("pos")
-	x = pos.Index(0).Float()
-	y = pos.Index(1).Float()
-	z = pos.Index(2).Float()
-	return
+	pos := g.PosVec()
+	return pos.X, pos.Y, pos.Z
 }
 
 func (g soundGroup) SetPos(x, y, z float64) {
 	g.value.Call("pos", x, y, z)
 }
 
+func (g soundGroup) PosVec() Vec3 {
+	return vec3Of(g.value.Call("pos"), 0)
+}
+
+func (g soundGroup) SetPosVec(pos Vec3) {
+	g.SetPos(pos.X, pos.Y, pos.Z)
+}
+
 func (g soundGroup) Orientation() (x, y, z float64) {
-	pos := g.value.Call("orientation")
-	x = pos.Index(0).Float()
-	y = pos.Index(1).Float()
-	z = pos.Index(2).Float()
-	return
+	orientation := g.OrientationVec()
+	return orientation.X, orientation.Y, orientation.Z
 }
 
 func (g soundGroup) SetOrientation(x, y, z float64) {
 	g.value.Call("orientation", x, y, z)
 }
 
+func (g soundGroup) OrientationVec() Vec3 {
+	return vec3Of(g.value.Call("orientation"), 0)
+}
+
+func (g soundGroup) SetOrientationVec(orientation Vec3) {
+	g.SetOrientation(orientation.X, orientation.Y, orientation.Z)
+}
+
 func (g soundGroup) PannerAttr() PannerAttr {
 	return PannerAttr{value: g.value.Call("pannerAttr")}
 }
@@ -172,16 +275,46 @@ func (g soundGroup) SetPannerAttr(attr PannerAttr) {
 }
 
 type soundSpecific struct {
-	id    int
-	value js.Value
+	id     int
+	value  Value
+	states *soundStates
 }
 
 func (s soundSpecific) ID() int {
 	return s.id
 }
 
+func (s soundSpecific) Valid() bool {
+	return s.value != nil
+}
+
+func (s soundSpecific) Err() error {
+	return nil
+}
+
+// State returns where the sound is in its lifecycle. While its Howl is still
+// loading, the Howl's state is returned instead.
 func (s soundSpecific) State() State {
-	return states[s.value.Call("state").String()]
+	sound := s.value.Call("_soundById", s.id)
+	if !sound.Truthy() {
+		s.states.forget(s.id)
+		return StateRecycled
+	}
+	if !sound.Get("_paused").Bool() {
+		return StatePlaying
+	}
+	if state := states[s.value.Call("state").String()]; state != StateLoaded {
+		return state
+	}
+	if !sound.Get("_ended").Bool() {
+		return StatePaused
+	}
+	// howler.js marks both stopped and ended sounds as ended, so only the
+	// events they fired tell them apart.
+	if state, ok := s.states.get(s.id); ok && state == StateEnded {
+		return StateEnded
+	}
+	return StateStopped
 }
 
 func (s soundSpecific) Playing() bool {
@@ -193,8 +326,21 @@ func (s soundSpecific) Duration() time.Duration {
 }
 
 func (s soundSpecific) Play() Sound {
-	s.value.Call("play", s.id)
-	return s
+	sound, _ := s.TryPlay()
+	return sound
+}
+
+func (s soundSpecific) TryPlay() (Sound, error) {
+	id, err := s.states.play(s.value, s.id, func() int {
+		return playedID(s.value.Call("play", s.id))
+	})
+	if err != nil {
+		return invalid(err)
+	}
+	if id < 0 {
+		return invalid(fmt.Errorf("%w: sound %d no longer exists", ErrNotPlayed, s.id))
+	}
+	return s, nil
 }
 
 func (s soundSpecific) Pause() {
@@ -202,6 +348,7 @@ func (s soundSpecific) Pause() {
 }
 
 func (s soundSpecific) Stop() {
+	s.states.interrupt(s.value, s.id)
 	s.value.Call("stop", s.id)
 }
 
@@ -213,16 +360,25 @@ func (s soundSpecific) Unmute() {
 	s.value.Call("mute", false, s.id)
 }
 
-func (s soundSpecific) Fade(from float64, to float64, duration time.Duration) {
-	s.value.Call("fade", from, to, duration.Milliseconds(), s.id)
+func (s soundSpecific) Fade(from float64, to float64, duration time.Duration) <-chan struct{} {
+	return s.FadeWith(from, to, duration, FadeLinear)
+}
+
+func (s soundSpecific) FadeWith(from float64, to float64, duration time.Duration, curve FadeCurve) <-chan struct{} {
+	return s.fadeThen(from, to, duration, curve, nil)
+}
+
+func (s soundSpecific) fadeThen(from, to float64, duration time.Duration, curve FadeCurve, then func()) <-chan struct{} {
+	return s.states.fade(s.value, s.id, from, to, duration, curve, then)
 }
 
 func (s soundSpecific) Volume() float64 {
-	return s.value.Call("volume", s.id).Float()
+	return s.states.getVolume(s.value, s.id)
 }
 
 func (s soundSpecific) SetVolume(volume float64) {
-	s.value.Call("volume", volume, s.id)
+	s.states.interrupt(s.value, s.id)
+	s.states.setVolume(s.value, s.id, volume, false)
 }
 
 func (s soundSpecific) Stereo() float64 {
@@ -258,27 +414,37 @@ func (s soundSpecific) SetLoop(loop bool) {
 }
 
 func (s soundSpecific) Pos() (x, y, z float64) {
-	pos := s.value.Call("pos", js.Null(), js.Null(), js.Null(), s.id)
-	x = pos.Index(0).Float()
-	y = pos.Index(1).Float()
-	z = pos.Index(2).Float()
-	return
+	pos := s.PosVec()
+	return pos.X, pos.Y, pos.Z
 }
 
 func (s soundSpecific) SetPos(x, y, z float64) {
-	s.value.Call("pos", x, y, z)
+	s.value.Call("pos", x, y, z, s.id)
+}
+
+func (s soundSpecific) PosVec() Vec3 {
+	return vec3Of(s.value.Call("pos", nil, nil, nil, s.id), 0)
+}
+
+func (s soundSpecific) SetPosVec(pos Vec3) {
+	s.SetPos(pos.X, pos.Y, pos.Z)
 }
 
 func (s soundSpecific) Orientation() (x, y, z float64) {
-	pos := s.value.Call("orientation", js.Null(), js.Null(), js.Null(), s.id)
-	x = pos.Index(0).Float()
-	y = pos.Index(1).Float()
-	z = pos.Index(2).Float()
-	return
+	orientation := s.OrientationVec()
+	return orientation.X, orientation.Y, orientation.Z
 }
 
 func (s soundSpecific) SetOrientation(x, y, z float64) {
-	s.value.Call("orientation", x, y, z)
+	s.value.Call("orientation", x, y, z, s.id)
+}
+
+func (s soundSpecific) OrientationVec() Vec3 {
+	return vec3Of(s.value.Call("orientation", nil, nil, nil, s.id), 0)
+}
+
+func (s soundSpecific) SetOrientationVec(orientation Vec3) {
+	s.SetOrientation(orientation.X, orientation.Y, orientation.Z)
 }
 
 func (s soundSpecific) PannerAttr() PannerAttr {
@@ -288,3 +454,60 @@ func (s soundSpecific) PannerAttr() PannerAttr {
 func (s soundSpecific) SetPannerAttr(attr PannerAttr) {
 	s.value.Call("pannerAttr", attr.value, s.id)
 }
+
+// invalidSound is the Sound returned when a sound couldn't be played.
+type invalidSound struct {
+	err error
+}
+
+// invalid returns an invalid Sound along with the error that made it invalid.
+func invalid(err error) (Sound, error) {
+	return invalidSound{err}, err
+}
+
+func (s invalidSound) ID() int                 { return -1 }
+func (s invalidSound) Valid() bool             { return false }
+func (s invalidSound) Err() error              { return s.err }
+func (s invalidSound) State() State            { return StateUnloaded }
+func (s invalidSound) Playing() bool           { return false }
+func (s invalidSound) Duration() time.Duration { return 0 }
+func (s invalidSound) Play() Sound             { return s }
+func (s invalidSound) TryPlay() (Sound, error) { return s, s.err }
+func (s invalidSound) Pause()                  {}
+func (s invalidSound) Stop()                   {}
+func (s invalidSound) Mute()                   {}
+func (s invalidSound) Unmute()                 {}
+func (s invalidSound) Fade(from, to float64, d time.Duration) <-chan struct{} {
+	return closedChan
+}
+func (s invalidSound) FadeWith(from, to float64, d time.Duration, curve FadeCurve) <-chan struct{} {
+	return closedChan
+}
+func (s invalidSound) fadeThen(from, to float64, d time.Duration, curve FadeCurve, then func()) <-chan struct{} {
+	return finished(then)
+}
+func (s invalidSound) Volume() float64                    { return 0 }
+func (s invalidSound) SetVolume(volume float64)           {}
+func (s invalidSound) Rate() float64                      { return 0 }
+func (s invalidSound) SetRate(rate float64)               {}
+func (s invalidSound) Seek() time.Duration                { return 0 }
+func (s invalidSound) SetSeek(position time.Duration)     {}
+func (s invalidSound) Loop() bool                         { return false }
+func (s invalidSound) SetLoop(loop bool)                  {}
+func (s invalidSound) Stereo() float64                    { return 0 }
+func (s invalidSound) SetStereo(stereo float64)           {}
+func (s invalidSound) Pos() (x, y, z float64)             { return 0, 0, 0 }
+func (s invalidSound) SetPos(x, y, z float64)             {}
+func (s invalidSound) PosVec() Vec3                       { return Vec3{} }
+func (s invalidSound) SetPosVec(pos Vec3)                 {}
+func (s invalidSound) Orientation() (x, y, z float64)     { return 0, 0, 0 }
+func (s invalidSound) SetOrientation(x, y, z float64)     {}
+func (s invalidSound) OrientationVec() Vec3               { return Vec3{} }
+func (s invalidSound) SetOrientationVec(orientation Vec3) {}
+func (s invalidSound) SetPannerAttr(attr PannerAttr)      {}
+
+// PannerAttr returns a detached set of default attributes, so reading or
+// changing them has no effect on any sound.
+func (s invalidSound) PannerAttr() PannerAttr {
+	return NewPannerAttr(PannerOptions{})
+}
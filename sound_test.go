@@ -0,0 +1,94 @@
+package howler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInvalidSound(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Second)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	played := h.Play()
+	b.Flush()
+	// howler.js forgets sounds once the Howl is unloaded.
+	h.Unload()
+
+	s, err := played.TryPlay()
+	if !errors.Is(err, ErrNotPlayed) {
+		t.Fatalf("TryPlay() of an unloaded sound = %v, want %v", err, ErrNotPlayed)
+	}
+	if s.Valid() || s.Err() != err || s.ID() != -1 {
+		t.Errorf("Valid(), Err(), ID() = %v, %v, %d; want false, %v, -1", s.Valid(), s.Err(), s.ID(), err)
+	}
+
+	// Every method is safe to call.
+	s.SetVolume(0.5)
+	s.Fade(0, 1, time.Second)
+	s.SetPosVec(Vec3{1, 2, 3})
+	s.PannerAttr().SetRefDistance(2)
+	s.Stop()
+	if s.Playing() || s.Volume() != 0 || s.PosVec() != (Vec3{}) || s.Play().Valid() {
+		t.Error("invalid Sound reported a playing sound")
+	}
+}
+
+func TestSoundState(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Second)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	b.Flush()
+
+	// Play every sound before any stops, as howler.js would otherwise reuse
+	// the stopped sound.
+	stopped := h.Play()
+	b.Advance(500 * time.Millisecond)
+	ended := h.Play()
+	paused := h.Play()
+	b.Advance(100 * time.Millisecond)
+	stopped.Stop()
+	paused.Pause()
+	b.Advance(time.Second)
+
+	steps := []struct {
+		name string
+		// want is the state of stopped, ended and paused.
+		want []State
+		// then moves on to the next step.
+		then func()
+	}{
+		{
+			name: "stopped, ended and paused",
+			want: []State{StateStopped, StateEnded, StatePaused},
+			then: func() { paused.Play() },
+		},
+		{
+			name: "resumed",
+			want: []State{StateStopped, StateEnded, StatePlaying},
+			// howler.js reuses the first inactive sound for a new one,
+			// giving it a new ID.
+			then: func() { h.Play() },
+		},
+		{
+			name: "first recycled",
+			want: []State{StateRecycled, StateEnded, StatePlaying},
+			then: func() { h.Play() },
+		},
+		{
+			name: "both recycled",
+			want: []State{StateRecycled, StateRecycled, StatePlaying},
+		},
+	}
+	for _, step := range steps {
+		b.Flush()
+		for i, sound := range []Sound{stopped, ended, paused} {
+			if got := sound.State(); got != step.want[i] {
+				t.Errorf("%s: sound %d is %v, want %v", step.name, i, got, step.want[i])
+			}
+		}
+		if step.then != nil {
+			step.then()
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package howler
+
+import (
+	"sort"
+	"sync"
+)
+
+// soundStates records the last lifecycle event fired for each sound of a Howl
+// and the fades in progress on them. The zero value is not usable; a nil
+// *soundStates records nothing.
+type soundStates struct {
+	backend Backend
+	mu      sync.Mutex
+	states  map[int]State
+	fades   map[int]*soundFade
+	skips   map[int]int
+
+	// bus is the bus the Howl is assigned to, and is only changed while
+	// holding both mixer and mu. While it is set, the Howl's own volume and
+	// the sounds with volumes of their own are tracked here, and gain scales
+	// them before they reach howler.js.
+	bus     *Bus
+	gain    float64
+	volume  float64
+	volumes map[int]float64
+
+	// limit and priority are the Howl's voice limit settings, starts
+	// numbers its sounds in the order they started playing, and pending holds
+	// the sounds played while howler.js waits for the Howl to load.
+	limit    VoiceLimit
+	priority int
+	starts   map[int]int64
+	pending  map[int]bool
+
+	// effects is the Howl's effect chain, and is only changed while holding
+	// both mixer and mu.
+	effects []Effect
+}
+
+func newSoundStates(backend Backend) *soundStates {
+	return &soundStates{
+		backend: backend,
+		states:  make(map[int]State),
+		fades:   make(map[int]*soundFade),
+		skips:   make(map[int]int),
+		starts:  make(map[int]int64),
+		pending: make(map[int]bool),
+	}
+}
+
+// track listens to the events of howl that change the state of its sounds.
+func (s *soundStates) track(howl Value, funcs *callbacks) {
+	listen := func(event Event, handler func(id int)) {
+		_, fn := funcs.add(func(this Value, args []Value) {
+			if id := soundID(args); id >= 0 {
+				handler(id)
+			}
+		})
+		howl.Call("on", string(event), fn)
+	}
+
+	listen(EventPlay, func(id int) {
+		s.setPending(id, false)
+		s.set(id, StatePlaying)
+		s.started(id)
+		s.route(howl, id)
+		// Drop sounds howler.js has since recycled so the map doesn't grow for
+		// as long as the Howl is used.
+		s.retain(soundIDs(howl))
+	})
+	listen(EventPause, func(id int) {
+		s.setPending(id, false)
+		s.set(id, StatePaused)
+	})
+	listen(EventStop, func(id int) {
+		s.setPending(id, false)
+		s.set(id, StateStopped)
+	})
+	listen(EventPlayError, func(id int) {
+		s.setPending(id, false)
+	})
+	// The sounds waiting for the Howl to load won't play if it fails.
+	_, fn := funcs.add(func(this Value, args []Value) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.pending = make(map[int]bool)
+	})
+	howl.Call("on", string(EventLoadError), fn)
+	listen(EventFade, s.fadeEnded)
+	listen(EventEnd, func(id int) {
+		// Looping sounds fire end at the end of every loop and carry on playing.
+		if howl.Call("playing", id).Bool() {
+			s.set(id, StatePlaying)
+		} else {
+			s.set(id, StateEnded)
+		}
+	})
+}
+
+func (s *soundStates) set(id int, state State) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[id] = state
+}
+
+func (s *soundStates) get(id int) (State, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[id]
+	return state, ok
+}
+
+func (s *soundStates) forget(id int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, id)
+}
+
+// retain forgets every sound except those in ids.
+func (s *soundStates) retain(ids []int) {
+	if s == nil {
+		return
+	}
+	keep := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.states {
+		if !keep[id] {
+			delete(s.states, id)
+		}
+	}
+	for id := range s.volumes {
+		if !keep[id] {
+			delete(s.volumes, id)
+		}
+	}
+	for id := range s.starts {
+		if !keep[id] {
+			delete(s.starts, id)
+		}
+	}
+	for id := range s.pending {
+		if !keep[id] {
+			delete(s.pending, id)
+		}
+	}
+}
+
+// clear forgets every sound, ending the fades in progress on them.
+func (s *soundStates) clear() {
+	if s == nil {
+		return
+	}
+	s.retain(nil)
+
+	var thens []func()
+	s.mu.Lock()
+	for id, f := range s.fades {
+		s.detach(f)
+		if then := s.finish(f); then != nil {
+			thens = append(thens, then)
+		}
+		delete(s.fades, id)
+	}
+	s.skips = make(map[int]int)
+	s.mu.Unlock()
+
+	for _, then := range thens {
+		then()
+	}
+}
+
+// playedID returns the id of the sound a call to play returned, or -1 if it
+// didn't play one.
+func playedID(result Value) int {
+	if !result.Truthy() {
+		return -1
+	}
+	return result.Int()
+}
+
+// soundIDs returns the ids of every sound howl holds.
+func soundIDs(howl Value) []int {
+	arr := howl.Call("_getSoundIds")
+	ids := make([]int, arr.Length())
+	for i := range ids {
+		ids[i] = arr.Index(i).Int()
+	}
+	return ids
+}
+
+// Sounds returns every sound the Howl holds in the order they were created,
+// including stopped and ended sounds that howler.js hasn't recycled yet. Use
+// State to tell them apart.
+func (h Howl) Sounds() []Sound {
+	ids := soundIDs(h.value)
+	sort.Ints(ids)
+	sounds := make([]Sound, len(ids))
+	for i, id := range ids {
+		sounds[i] = soundSpecific{
+			id:     id,
+			value:  h.value,
+			states: h.states,
+		}
+	}
+	return sounds
+}
@@ -1,10 +1,48 @@
 package howler
 
 import (
+	"encoding/json"
 	"fmt"
-	"syscall/js"
 )
 
+// Vec3 is a position or direction in 3D cartesian space. It is encoded in JSON
+// as an [x, y, z] array.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+func (v Vec3) MarshalJSON() ([]byte, error) {
+	return json.Marshal([3]float64{v.X, v.Y, v.Z})
+}
+
+// UnmarshalJSON decodes an [x, y, z] array, rejecting arrays of any other
+// length.
+func (v *Vec3) UnmarshalJSON(data []byte) error {
+	var arr []float64
+	if err := json.Unmarshal(data, &arr); err != nil {
+		return err
+	}
+	if len(arr) != 3 {
+		return fmt.Errorf("howler: Vec3 has %d elements, expected 3", len(arr))
+	}
+	*v = Vec3{arr[0], arr[1], arr[2]}
+	return nil
+}
+
+// vec3Of reads a Vec3 from the first three elements of an array. howler.js
+// returns null for positions that have never been set, which reads as the
+// origin.
+func vec3Of(arr Value, offset int) Vec3 {
+	if arr.Type() != TypeObject {
+		return Vec3{}
+	}
+	return Vec3{
+		X: arr.Index(offset).Float(),
+		Y: arr.Index(offset + 1).Float(),
+		Z: arr.Index(offset + 2).Float(),
+	}
+}
+
 type DistanceModel int
 
 const (
@@ -14,6 +52,37 @@ const (
 	DistanceModelExponential
 )
 
+// String returns the name howler.js uses for the distance model.
+func (m DistanceModel) String() string {
+	switch m {
+	case DistanceModelLinear:
+		return "linear"
+	case DistanceModelInverse:
+		return "inverse"
+	case DistanceModelExponential:
+		return "exponential"
+	default:
+		return ""
+	}
+}
+
+func (m DistanceModel) MarshalText() ([]byte, error) {
+	if m != DistanceModelUndefined && m.String() == "" {
+		return nil, fmt.Errorf("unknown distance model: %d", m)
+	}
+	return []byte(m.String()), nil
+}
+
+func (m *DistanceModel) UnmarshalText(text []byte) error {
+	for _, model := range []DistanceModel{DistanceModelUndefined, DistanceModelLinear, DistanceModelInverse, DistanceModelExponential} {
+		if model.String() == string(text) {
+			*m = model
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown distance model: %q", text)
+}
+
 type PanningModel int
 
 const (
@@ -22,19 +91,48 @@ const (
 	PanningModelEqualPower
 )
 
+// String returns the name howler.js uses for the panning model.
+func (m PanningModel) String() string {
+	switch m {
+	case PanningModelHRTF:
+		return "HRTF"
+	case PanningModelEqualPower:
+		return "equalpower"
+	default:
+		return ""
+	}
+}
+
+func (m PanningModel) MarshalText() ([]byte, error) {
+	if m != PanningModelUndefined && m.String() == "" {
+		return nil, fmt.Errorf("unknown panning model: %d", m)
+	}
+	return []byte(m.String()), nil
+}
+
+func (m *PanningModel) UnmarshalText(text []byte) error {
+	for _, model := range []PanningModel{PanningModelUndefined, PanningModelHRTF, PanningModelEqualPower} {
+		if model.String() == string(text) {
+			*m = model
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown panning model: %q", text)
+}
+
 type PannerOptions struct {
 	// ConeInnerAngle is a parameter for directional audio sources, this is an angle, in
 	// degrees, inside of which there will be no volume reduction. default: 360
-	ConeInnerAngle OptionalFloat `json:"cone_inner_angle,omitempty"`
+	ConeInnerAngle Optional[float64] `json:"cone_inner_angle,omitzero"`
 
 	// ConeOuterAngle is a parameter for directional audio sources, this is an angle, in
 	// degrees, outside of which the volume will be reduced to a constant value of
 	// ConeOuterGain. default: 360
-	ConeOuterAngle OptionalFloat `json:"cone_outer_angle,omitempty"`
+	ConeOuterAngle Optional[float64] `json:"cone_outer_angle,omitzero"`
 
 	// ConeOuterGain is a parameter for directional audio sources, this is the gain
 	// outside of the coneOuterAngle. It is a linear value in the range [0, 1].
-	ConeOuterGain OptionalFloat `json:"cone_outer_gain,omitempty"`
+	ConeOuterGain Optional[float64] `json:"cone_outer_gain,omitzero"`
 
 	// DistanceModel determines algorithm used to reduce volume as audio moves away
 	// from listener. Can be DistanceModelLinear, DistanceModelInverse or DistanceModelExponential.
@@ -42,26 +140,82 @@ type PannerOptions struct {
 
 	// The maximum distance between source and listener, after which the volume will
 	// not be reduced any further. default: 10000
-	MaxDistance OptionalFloat `json:"max_distance,omitempty"`
+	MaxDistance Optional[float64] `json:"max_distance,omitzero"`
 
 	// A reference distance for reducing volume as source moves further from the
 	// listener. This is simply a variable of the distance model and has a different
 	// effect depending on which model is used and the scale of your coordinates.
 	// Generally, volume will be equal to 1 at this distance. default: 1
-	RefDistance OptionalFloat `json:"ref_distance,omitempty"`
+	RefDistance Optional[float64] `json:"ref_distance,omitzero"`
 
 	// How quickly the volume reduces as source moves from listener. This is simply a
 	// variable of the distance model and can be in the range of [0, 1] with linear
 	// and [0, ∞] with inverse and exponential.
-	RolloffFactor OptionalFloat `json:"rolloff_factor,omitempty"`
+	RolloffFactor Optional[float64] `json:"rolloff_factor,omitzero"`
 
 	// Determines which spatialization algorithm is used to position audio. Can be
 	// PanningModelHRTF or PanningModelEqualPower.
 	PanningModel PanningModel `json:"panning_model,omitempty"`
 }
 
+// attributes returns the panner attributes that have been set, keyed by their
+// howler.js names.
+func (o PannerOptions) attributes() map[string]any {
+	attr := make(map[string]any)
+	if v, ok := o.ConeInnerAngle.Get(); ok {
+		attr["coneInnerAngle"] = v
+	}
+	if v, ok := o.ConeOuterAngle.Get(); ok {
+		attr["coneOuterAngle"] = v
+	}
+	if v, ok := o.ConeOuterGain.Get(); ok {
+		attr["coneOuterGain"] = v
+	}
+	if o.DistanceModel != DistanceModelUndefined {
+		attr["distanceModel"] = o.DistanceModel.String()
+	}
+	if v, ok := o.MaxDistance.Get(); ok {
+		attr["maxDistance"] = v
+	}
+	if v, ok := o.RefDistance.Get(); ok {
+		attr["refDistance"] = v
+	}
+	if v, ok := o.RolloffFactor.Get(); ok {
+		attr["rolloffFactor"] = v
+	}
+	if o.PanningModel != PanningModelUndefined {
+		attr["panningModel"] = o.PanningModel.String()
+	}
+	return attr
+}
+
+// defaultPannerAttr holds the panner attributes howler.js gives a new Howl.
+var defaultPannerAttr = map[string]any{
+	"coneInnerAngle": 360,
+	"coneOuterAngle": 360,
+	"coneOuterGain":  0,
+	"distanceModel":  "inverse",
+	"maxDistance":    10000,
+	"refDistance":    1,
+	"rolloffFactor":  1,
+	"panningModel":   "HRTF",
+}
+
 type PannerAttr struct {
-	value js.Value
+	value Value
+}
+
+// NewPannerAttr builds panner attributes from opts, ready to be passed to
+// SetPannerAttr. Attributes left unset in opts take howler's defaults.
+func NewPannerAttr(opts PannerOptions) PannerAttr {
+	value := backend.NewObject()
+	for k, v := range defaultPannerAttr {
+		value.Set(k, v)
+	}
+	for k, v := range opts.attributes() {
+		value.Set(k, v)
+	}
+	return PannerAttr{value: value}
 }
 
 func (a PannerAttr) ConeInnerAngle() float64 {
@@ -102,16 +256,10 @@ func (a PannerAttr) DistanceModel() DistanceModel {
 }
 
 func (a PannerAttr) SetDistanceModel(model DistanceModel) {
-	switch model {
-	case DistanceModelLinear:
-		a.value.Set("distanceModel", "linear")
-	case DistanceModelInverse:
-		a.value.Set("distanceModel", "inverse")
-	case DistanceModelExponential:
-		a.value.Set("distanceModel", "exponential")
-	default:
+	if model.String() == "" {
 		panic(fmt.Errorf("unknown distance model: %d", model))
 	}
+	a.value.Set("distanceModel", model.String())
 }
 
 func (a PannerAttr) MaxDistance() float64 {
@@ -150,12 +298,8 @@ func (a PannerAttr) PanningModel() PanningModel {
 }
 
 func (a PannerAttr) SetPanningModel(model PanningModel) {
-	switch model {
-	case PanningModelHRTF:
-		a.value.Set("panningModel", "HRTF")
-	case PanningModelEqualPower:
-		a.value.Set("panningModel", "equalpower")
-	default:
+	if model.String() == "" {
 		panic(fmt.Errorf("unknown panning model: %d", model))
 	}
+	a.value.Set("panningModel", model.String())
 }
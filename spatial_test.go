@@ -0,0 +1,142 @@
+package howler
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVec3JSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Vec3
+		wantErr bool
+	}{
+		{name: "three", in: "[1,2,3]", want: Vec3{1, 2, 3}},
+		{name: "fractions", in: "[-0.5,0,2.25]", want: Vec3{-0.5, 0, 2.25}},
+		{name: "two", in: "[1,2]", wantErr: true},
+		{name: "four", in: "[1,2,3,4]", wantErr: true},
+		{name: "empty", in: "[]", wantErr: true},
+		{name: "object", in: `{"x":1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Vec3
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Unmarshal(%s) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back Vec3
+			if err := json.Unmarshal(data, &back); err != nil || back != got {
+				t.Errorf("round trip of %v gave %v, %v", got, back, err)
+			}
+		})
+	}
+}
+
+func TestVec3Setters(t *testing.T) {
+	newTestBackend(t)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	a := h.Play()
+	b := h.Play()
+
+	a.SetPosVec(Vec3{1, 2, 3})
+	b.SetOrientationVec(Vec3{0, 0, -1})
+
+	if got := a.PosVec(); got != (Vec3{1, 2, 3}) {
+		t.Errorf("a.PosVec() = %v, want {1 2 3}", got)
+	}
+	if got := b.PosVec(); got != (Vec3{}) {
+		t.Errorf("b.PosVec() = %v, want the origin", got)
+	}
+	if got := b.OrientationVec(); got != (Vec3{0, 0, -1}) {
+		t.Errorf("b.OrientationVec() = %v, want {0 0 -1}", got)
+	}
+
+	SetPosVec(Vec3{4, 5, 6})
+	if got := PosVec(); got != (Vec3{4, 5, 6}) {
+		t.Errorf("PosVec() = %v, want {4 5 6}", got)
+	}
+}
+
+// pannerValues lists the attributes of a in the order of PannerOptions.
+type pannerValues struct {
+	coneInnerAngle, coneOuterAngle, coneOuterGain float64
+	distanceModel                                 DistanceModel
+	maxDistance, refDistance, rolloffFactor       float64
+	panningModel                                  PanningModel
+}
+
+func pannerValuesOf(a PannerAttr) pannerValues {
+	return pannerValues{
+		a.ConeInnerAngle(), a.ConeOuterAngle(), a.ConeOuterGain(),
+		a.DistanceModel(),
+		a.MaxDistance(), a.RefDistance(), a.RolloffFactor(),
+		a.PanningModel(),
+	}
+}
+
+func TestPannerOptions(t *testing.T) {
+	defaults := pannerValues{360, 360, 0, DistanceModelInverse, 10000, 1, 1, PanningModelHRTF}
+	tests := []struct {
+		name string
+		opts PannerOptions
+		want pannerValues
+	}{
+		{name: "defaults", want: defaults},
+		{
+			name: "some",
+			opts: PannerOptions{
+				RefDistance:   Some(5.0),
+				DistanceModel: DistanceModelLinear,
+			},
+			want: pannerValues{360, 360, 0, DistanceModelLinear, 10000, 5, 1, PanningModelHRTF},
+		},
+		{
+			name: "all",
+			opts: PannerOptions{
+				ConeInnerAngle: Some(90.0),
+				ConeOuterAngle: Some(180.0),
+				ConeOuterGain:  Some(0.25),
+				DistanceModel:  DistanceModelExponential,
+				MaxDistance:    Some(100.0),
+				RefDistance:    Some(2.0),
+				RolloffFactor:  Some(0.5),
+				PanningModel:   PanningModelEqualPower,
+			},
+			want: pannerValues{90, 180, 0.25, DistanceModelExponential, 100, 2, 0.5, PanningModelEqualPower},
+		},
+		{
+			name: "zero values",
+			opts: PannerOptions{ConeOuterAngle: Some(0.0), RolloffFactor: Some(0.0)},
+			want: pannerValues{360, 0, 0, DistanceModelInverse, 10000, 1, 0, PanningModelHRTF},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestBackend(t)
+			if got := pannerValuesOf(NewPannerAttr(tt.opts)); got != tt.want {
+				t.Errorf("NewPannerAttr() = %+v, want %+v", got, tt.want)
+			}
+
+			h := New(HowlOptions{Source: []string{"a.mp3"}, PannerOptions: tt.opts})
+			if got := pannerValuesOf(h.PannerAttr()); got != tt.want {
+				t.Errorf("New() gave the Howl %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,44 +1,165 @@
 package howler
 
 import (
-	"errors"
-	"fmt"
 	"reflect"
-	"syscall/js"
+	"sync"
+	"sync/atomic"
 )
 
 type CallbackFunc func()
+
+// CallbackErrorFunc receives a *LoadError from OnLoadError and a *PlayError
+// from OnPlayError.
 type CallbackErrorFunc func(error)
 
-type OptionalInt = any
-type OptionalFloat = any
-type OptionalBool = any
-type OptionalString = any
+// live counts the callbacks that have been created but not yet released.
+var live int64
+
+// LiveCallbacks returns the number of Go callbacks currently registered with
+// howler.js. Each one pins a Go closure until it is released, so this should
+// return to its previous value once a Howl has been unloaded.
+func LiveCallbacks() int {
+	return int(atomic.LoadInt64(&live))
+}
+
+// registries holds the callbacks of every Howl that hasn't been unloaded, so
+// that they can be released by the global Unload, along with the Howl they are
+// registered on once it has been created.
+var registries = struct {
+	sync.Mutex
+	m map[*callbacks]Value
+}{m: make(map[*callbacks]Value)}
+
+// callbacks owns the functions registered on behalf of a single Howl.
+type callbacks struct {
+	backend Backend
+	mu      sync.Mutex
+	next    int
+	funcs   map[int]Func
+}
+
+func newCallbacks(backend Backend) *callbacks {
+	c := &callbacks{backend: backend, funcs: make(map[int]Func)}
+	registries.Lock()
+	registries.m[c] = nil
+	registries.Unlock()
+	return c
+}
+
+// attach records howl as the Howl c's functions are registered on.
+func (c *callbacks) attach(howl Value) {
+	registries.Lock()
+	defer registries.Unlock()
+	if _, ok := registries.m[c]; ok {
+		registries.m[c] = howl
+	}
+}
+
+// add wraps fn in a Func owned by c and returns its id along with the function
+// itself.
+func (c *callbacks) add(fn func(this Value, args []Value)) (int, Func) {
+	f := c.backend.FuncOf(fn)
+	atomic.AddInt64(&live, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	c.funcs[c.next] = f
+	return c.next, f
+}
+
+// get returns the function registered with id.
+func (c *callbacks) get(id int) (Func, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.funcs[id]
+	return f, ok
+}
 
-func setCallback(value js.Value, event string, callback any) {
-	var fn js.Func
+// release releases the function registered with id, if it hasn't been already.
+func (c *callbacks) release(id int) {
+	c.mu.Lock()
+	f, ok := c.funcs[id]
+	delete(c.funcs, id)
+	c.mu.Unlock()
+
+	if ok {
+		f.Release()
+		atomic.AddInt64(&live, -1)
+	}
+}
+
+// releaseAll releases every function owned by c and forgets about c.
+func (c *callbacks) releaseAll() {
+	if c == nil {
+		return
+	}
+
+	registries.Lock()
+	delete(registries.m, c)
+	registries.Unlock()
+
+	c.mu.Lock()
+	funcs := c.funcs
+	c.funcs = make(map[int]Func)
+	c.mu.Unlock()
+
+	for _, f := range funcs {
+		f.Release()
+		atomic.AddInt64(&live, -1)
+	}
+}
+
+// offAll removes every listener from every Howl, so that the events howler.js
+// fires while unloading them don't reach released callbacks.
+func offAll() {
+	registries.Lock()
+	howls := make([]Value, 0, len(registries.m))
+	for _, howl := range registries.m {
+		if howl != nil {
+			howls = append(howls, howl)
+		}
+	}
+	registries.Unlock()
+
+	for _, howl := range howls {
+		howl.Call("off")
+	}
+}
+
+// releaseAllCallbacks releases the callbacks of every Howl.
+func releaseAllCallbacks() {
+	registries.Lock()
+	all := make([]*callbacks, 0, len(registries.m))
+	for c := range registries.m {
+		all = append(all, c)
+	}
+	registries.Unlock()
+
+	for _, c := range all {
+		c.releaseAll()
+	}
+}
+
+func setCallback(funcs *callbacks, value Value, event string, callback any) {
+	var fn Func
 
 	if reflect.ValueOf(callback).IsNil() {
 		return
 	}
 
 	switch callback := callback.(type) {
-	case nil:
-		fmt.Println(value, event, "nil")
-		return
 	case CallbackFunc:
-		fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		_, fn = funcs.add(func(this Value, args []Value) {
 			callback()
-			return nil
 		})
 	case CallbackErrorFunc:
-		fn = js.FuncOf(func(this js.Value, args []js.Value) any {
-			callback(errors.New(args[1].String()))
-			return nil
+		_, fn = funcs.add(func(this Value, args []Value) {
+			callback(eventError(event, this, args))
 		})
 	}
 
-	if fn.Truthy() {
+	if fn != nil {
 		value.Set(event, fn)
 	}
 }
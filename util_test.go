@@ -0,0 +1,29 @@
+package howler
+
+import "testing"
+
+func TestLiveCallbacks(t *testing.T) {
+	newTestBackend(t)
+	base := LiveCallbacks()
+
+	h := New(HowlOptions{Source: []string{"a.mp3"}, OnEnd: func() {}})
+	created := LiveCallbacks()
+	if created <= base {
+		t.Fatalf("LiveCallbacks() = %d after New, want more than %d", created, base)
+	}
+
+	sub := h.On(EventPlay, func(Sound) {})
+	if got := LiveCallbacks(); got != created+1 {
+		t.Errorf("LiveCallbacks() = %d after On, want %d", got, created+1)
+	}
+	h.Off(sub)
+	h.Off(sub)
+	if got := LiveCallbacks(); got != created {
+		t.Errorf("LiveCallbacks() = %d after Off, want %d", got, created)
+	}
+
+	h.Unload()
+	if got := LiveCallbacks(); got != base {
+		t.Errorf("LiveCallbacks() = %d after Unload, want %d", got, base)
+	}
+}
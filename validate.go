@@ -0,0 +1,174 @@
+package howler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a problem with a single field of HowlOptions.
+type FieldError struct {
+	// Field is the path to the field, such as Rate or Sprites["laser"].Offset.
+	Field string
+	// Message describes what is wrong with the field.
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every problem found by HowlOptions.Validate.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "howler: invalid options: " + strings.Join(msgs, "; ")
+}
+
+// validator collects the problems found with a set of options.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) errorf(field string, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) between(field string, o Optional[float64], min, max float64) {
+	if value, ok := o.Get(); ok && (value < min || value > max) {
+		v.errorf(field, "%g is outside the range %g to %g", value, min, max)
+	}
+}
+
+func (v *validator) vector(field string, o Optional[Vec3], direction bool) {
+	vec, ok := o.Get()
+	if !ok {
+		return
+	}
+	for _, c := range []float64{vec.X, vec.Y, vec.Z} {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			v.errorf(field, "%v has a component that isn't finite", vec)
+			return
+		}
+	}
+	if direction && vec == (Vec3{}) {
+		v.errorf(field, "is the zero vector, which has no direction")
+	}
+}
+
+// Validate checks the options for mistakes that howler.js would otherwise
+// silently ignore or misbehave on, reporting every problem found as a
+// *ValidationError.
+func (opts HowlOptions) Validate() error {
+	var v validator
+
+	if len(opts.Source) == 0 {
+		v.errorf("Source", "at least one source is required")
+	}
+	for i, src := range opts.Source {
+		if src == "" {
+			v.errorf(fmt.Sprintf("Source[%d]", i), "is empty")
+		}
+	}
+	if len(opts.Format) > len(opts.Source) {
+		v.errorf("Format", "has %d formats for %d sources", len(opts.Format), len(opts.Source))
+	}
+
+	v.between("Volume", opts.Volume, 0, 1)
+	v.between("Rate", opts.Rate, 0.5, 4)
+	v.between("Stereo", opts.Stereo, -1, 1)
+	if pool, ok := opts.Pool.Get(); ok && pool < 0 {
+		v.errorf("Pool", "%d is negative", pool)
+	}
+	v.vector("Pos", opts.Pos, false)
+	v.vector("Orientation", opts.Orientation, true)
+
+	v.sprites(opts.Sprites)
+	v.panner("PannerOptions", opts.PannerOptions)
+
+	if len(v.errs) > 0 {
+		return &ValidationError{Errors: v.errs}
+	}
+	return nil
+}
+
+func (v *validator) sprites(sprites map[string]Sprite) {
+	names := make([]string, 0, len(sprites))
+	for name := range sprites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sprite := sprites[name]
+		field := fmt.Sprintf("Sprites[%q]", name)
+		if name == "" {
+			v.errorf(field, "name is empty")
+		}
+		if sprite.Offset < 0 {
+			v.errorf(field+".Offset", "%v is negative", sprite.Offset)
+		}
+		if sprite.Duration <= 0 {
+			v.errorf(field+".Duration", "%v is not positive", sprite.Duration)
+		}
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return sprites[names[i]].Offset < sprites[names[j]].Offset
+	})
+	// Compare each sprite with whichever before it reaches furthest, as a long
+	// sprite can overlap several that follow it.
+	var furthest string
+	for i, name := range names {
+		cur := sprites[name]
+		if i > 0 {
+			prev := sprites[furthest]
+			if cur.Offset < prev.Offset+prev.Duration {
+				v.errorf(fmt.Sprintf("Sprites[%q]", name), "overlaps sprite %q", furthest)
+			}
+		}
+		if i == 0 || cur.Offset+cur.Duration > sprites[furthest].Offset+sprites[furthest].Duration {
+			furthest = name
+		}
+	}
+}
+
+func (v *validator) panner(field string, o PannerOptions) {
+	v.between(field+".ConeInnerAngle", o.ConeInnerAngle, 0, 360)
+	v.between(field+".ConeOuterAngle", o.ConeOuterAngle, 0, 360)
+	v.between(field+".ConeOuterGain", o.ConeOuterGain, 0, 1)
+	if d, ok := o.MaxDistance.Get(); ok && d <= 0 {
+		v.errorf(field+".MaxDistance", "%g is not positive", d)
+	}
+	if d, ok := o.RefDistance.Get(); ok && d < 0 {
+		v.errorf(field+".RefDistance", "%g is negative", d)
+	}
+	if o.DistanceModel == DistanceModelLinear {
+		v.between(field+".RolloffFactor", o.RolloffFactor, 0, 1)
+	} else if f, ok := o.RolloffFactor.Get(); ok && f < 0 {
+		v.errorf(field+".RolloffFactor", "%g is negative", f)
+	}
+	if o.DistanceModel != DistanceModelUndefined && o.DistanceModel.String() == "" {
+		v.errorf(field+".DistanceModel", "unknown distance model: %d", o.DistanceModel)
+	}
+	if o.PanningModel != PanningModelUndefined && o.PanningModel.String() == "" {
+		v.errorf(field+".PanningModel", "unknown panning model: %d", o.PanningModel)
+	}
+}
+
+// NewChecked validates opts before creating a Howl from them, so that
+// misconfigured sounds are caught before they reach howler.js. If opts are
+// invalid the returned Howl is too, and does nothing.
+func NewChecked(opts HowlOptions) (Howl, error) {
+	if err := opts.Validate(); err != nil {
+		return invalidHowl(err), err
+	}
+	return New(opts), nil
+}
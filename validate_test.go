@@ -0,0 +1,118 @@
+package howler
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() HowlOptions {
+		return HowlOptions{Source: []string{"a.webm", "a.mp3"}}
+	}
+	tests := []struct {
+		name   string
+		modify func(*HowlOptions)
+		fields []string
+	}{
+		{name: "valid", modify: func(o *HowlOptions) {}},
+		{name: "no source", modify: func(o *HowlOptions) { o.Source = nil }, fields: []string{"Source"}},
+		{name: "empty source", modify: func(o *HowlOptions) { o.Source[1] = "" }, fields: []string{"Source[1]"}},
+		{name: "too many formats", modify: func(o *HowlOptions) { o.Format = []string{"webm", "mp3", "ogg"} }, fields: []string{"Format"}},
+		{name: "volume", modify: func(o *HowlOptions) { o.Volume = Some(1.5) }, fields: []string{"Volume"}},
+		{name: "rate", modify: func(o *HowlOptions) { o.Rate = Some(0.1) }, fields: []string{"Rate"}},
+		{name: "stereo", modify: func(o *HowlOptions) { o.Stereo = Some(-2.0) }, fields: []string{"Stereo"}},
+		{name: "pool", modify: func(o *HowlOptions) { o.Pool = Some(-1) }, fields: []string{"Pool"}},
+		{name: "pos", modify: func(o *HowlOptions) { o.Pos = Some(Vec3{1, math.NaN(), 0}) }, fields: []string{"Pos"}},
+		{name: "pos at origin", modify: func(o *HowlOptions) { o.Pos = Some(Vec3{}) }},
+		{name: "orientation", modify: func(o *HowlOptions) { o.Orientation = Some(Vec3{math.Inf(1), 0, 0}) }, fields: []string{"Orientation"}},
+		{name: "zero orientation", modify: func(o *HowlOptions) { o.Orientation = Some(Vec3{}) }, fields: []string{"Orientation"}},
+		{
+			name: "sprites",
+			modify: func(o *HowlOptions) {
+				o.Sprites = map[string]Sprite{
+					"a": {Offset: 0, Duration: time.Second},
+					"b": {Offset: 500 * time.Millisecond, Duration: time.Second},
+					"c": {Offset: -time.Second, Duration: 0},
+				}
+			},
+			fields: []string{`Sprites["c"].Offset`, `Sprites["c"].Duration`, `Sprites["b"]`},
+		},
+		{
+			name: "sprite inside another",
+			modify: func(o *HowlOptions) {
+				o.Sprites = map[string]Sprite{
+					"a": {Offset: 0, Duration: 10 * time.Second},
+					"b": {Offset: time.Second, Duration: time.Second},
+					"c": {Offset: 3 * time.Second, Duration: time.Second},
+					"d": {Offset: 10 * time.Second, Duration: time.Second},
+				}
+			},
+			fields: []string{`Sprites["b"]`, `Sprites["c"]`},
+		},
+		{
+			name:   "panner",
+			modify: func(o *HowlOptions) { o.PannerOptions.ConeOuterGain = Some(2.0) },
+			fields: []string{"PannerOptions.ConeOuterGain"},
+		},
+		{
+			name: "several",
+			modify: func(o *HowlOptions) {
+				o.Volume = Some(-1.0)
+				o.Pool = Some(-1)
+			},
+			fields: []string{"Volume", "Pool"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid()
+			tt.modify(&opts)
+			err := opts.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			var got []string
+			for _, e := range verr.Errors {
+				got = append(got, e.Field)
+			}
+			if !equalStrings(got, tt.fields) {
+				t.Errorf("fields = %q, want %q", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestNewChecked(t *testing.T) {
+	newTestBackend(t)
+	if _, err := NewChecked(HowlOptions{}); err == nil {
+		t.Error("NewChecked with no source succeeded")
+	}
+	h, err := NewChecked(HowlOptions{Source: []string{"a.mp3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.Valid() {
+		t.Error("Howl from NewChecked isn't valid")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,333 @@
+package howler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// StealPolicy chooses which playing sound makes way for a new one once a voice
+// limit has been reached.
+type StealPolicy int
+
+const (
+	// StealNone rejects the new sound with ErrVoiceLimit.
+	StealNone StealPolicy = iota
+	// StealOldest stops the sound that started playing first.
+	StealOldest
+	// StealQuietest stops the sound with the lowest volume.
+	StealQuietest
+	// StealLowestPriority stops the sound whose Howl has the lowest priority,
+	// as long as it isn't higher than the new sound's. The new sound is
+	// rejected if every playing sound has a higher priority.
+	StealLowestPriority
+	// StealFarthest stops the sound farthest from the listener, using the
+	// spatial positions set with Pos and SetPos. Sounds without a position
+	// count as being at the origin.
+	StealFarthest
+)
+
+func (p StealPolicy) String() string {
+	switch p {
+	case StealNone:
+		return "none"
+	case StealOldest:
+		return "oldest"
+	case StealQuietest:
+		return "quietest"
+	case StealLowestPriority:
+		return "lowest priority"
+	case StealFarthest:
+		return "farthest"
+	default:
+		return fmt.Sprintf("StealPolicy(%d)", int(p))
+	}
+}
+
+// VoiceLimit caps how many sounds can play at once. Playing a sound past the
+// limit either stops a playing sound chosen by Steal to make way for it, or
+// fails with ErrVoiceLimit. Ties are broken in favour of stopping the sound
+// that started first, so the same plays always steal the same sounds.
+type VoiceLimit struct {
+	// Max is the most sounds that can play at once. Zero means no limit.
+	Max int `json:"max,omitempty"`
+	// Steal chooses the sound to stop once Max sounds are playing.
+	Steal StealPolicy `json:"steal,omitempty"`
+}
+
+// voices holds every Howl that hasn't been unloaded, so that the global voice
+// limit can count their sounds.
+var voices = struct {
+	sync.Mutex
+	// admitting is held from admitting a sound until it has been played, so
+	// that the next sound admitted counts it.
+	admitting sync.Mutex
+	howls     map[*soundStates]Value
+	limit     VoiceLimit
+	// seq numbers the sounds in the order they started playing.
+	seq int64
+}{howls: make(map[*soundStates]Value)}
+
+// CurrentVoiceLimit returns the limit on how many sounds every Howl can play
+// at once between them.
+func CurrentVoiceLimit() VoiceLimit {
+	voices.Lock()
+	defer voices.Unlock()
+	return voices.limit
+}
+
+// SetVoiceLimit limits how many sounds every Howl can play at once between
+// them. Sounds already playing past the limit carry on.
+func SetVoiceLimit(limit VoiceLimit) {
+	voices.Lock()
+	defer voices.Unlock()
+	voices.limit = limit
+}
+
+// VoiceLimit returns the limit on how many of the Howl's sounds can play at
+// once.
+func (h Howl) VoiceLimit() VoiceLimit {
+	h.states.mu.Lock()
+	defer h.states.mu.Unlock()
+	return h.states.limit
+}
+
+// SetVoiceLimit limits how many of the Howl's sounds can play at once. Sounds
+// already playing past the limit carry on.
+func (h Howl) SetVoiceLimit(limit VoiceLimit) {
+	h.states.mu.Lock()
+	defer h.states.mu.Unlock()
+	h.states.limit = limit
+}
+
+// Priority returns the priority of the Howl's sounds, used by
+// StealLowestPriority.
+func (h Howl) Priority() int {
+	h.states.mu.Lock()
+	defer h.states.mu.Unlock()
+	return h.states.priority
+}
+
+// SetPriority sets the priority of the Howl's sounds, used by
+// StealLowestPriority. Higher priorities win.
+func (h Howl) SetPriority(priority int) {
+	h.states.mu.Lock()
+	defer h.states.mu.Unlock()
+	h.states.priority = priority
+}
+
+// register adds the Howl to those counted by the global voice limit.
+func (s *soundStates) register(howl Value) {
+	voices.Lock()
+	defer voices.Unlock()
+	voices.howls[s] = howl
+}
+
+// unregister removes the Howl from those counted by the global voice limit.
+func (s *soundStates) unregister() {
+	voices.Lock()
+	defer voices.Unlock()
+	delete(voices.howls, s)
+}
+
+// unregisterAll removes every Howl from those counted by the global voice
+// limit.
+func unregisterAll() {
+	voices.Lock()
+	defer voices.Unlock()
+	voices.howls = make(map[*soundStates]Value)
+}
+
+// play makes way for sound id of howl to start playing, or a new sound if id
+// is -1, then plays it with play, which returns the id of the sound played or
+// -1 if none was. Sounds are admitted one at a time, and a sound howler.js
+// holds back until the Howl has loaded counts as playing, so plays made
+// together can't go past a limit.
+func (s *soundStates) play(howl Value, id int, play func() int) (int, error) {
+	if s == nil {
+		return play(), nil
+	}
+	voices.admitting.Lock()
+	defer voices.admitting.Unlock()
+	if err := s.admit(howl, id); err != nil {
+		return -1, err
+	}
+	played := play()
+	if played < 0 {
+		return -1, nil
+	}
+	s.started(played)
+	if !howl.Call("playing", played).Bool() {
+		s.setPending(played, true)
+	}
+	s.route(howl, played)
+	return played, nil
+}
+
+// setPending records whether sound id has been played but is waiting for
+// howler.js to start it.
+func (s *soundStates) setPending(id int, pending bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pending {
+		s.pending[id] = true
+	} else {
+		delete(s.pending, id)
+	}
+}
+
+// voice is a playing sound counted against a voice limit.
+type voice struct {
+	states   *soundStates
+	howl     Value
+	id       int
+	priority int
+	started  int64
+	// volume and distance are filled in by steal for the policies that
+	// compare them. distance is squared, which orders sounds the same way.
+	volume   float64
+	distance float64
+}
+
+// admit makes way for sound id of howl to start playing, or a new sound if id
+// is -1, stopping the sounds the voice limits steal. It returns ErrVoiceLimit
+// if the sound can't be played. The caller must hold voices.admitting.
+func (s *soundStates) admit(howl Value, id int) error {
+	if id >= 0 && howl.Call("playing", id).Bool() {
+		return nil
+	}
+
+	voices.Lock()
+	s.mu.Lock()
+	limit, priority := s.limit, s.priority
+	s.mu.Unlock()
+
+	var stolen []voice
+	ok := true
+	if limit.Max > 0 {
+		stolen, ok = steal(playingVoices(map[*soundStates]Value{s: howl}, nil), limit, priority)
+	}
+	if ok && voices.limit.Max > 0 {
+		var more []voice
+		more, ok = steal(playingVoices(voices.howls, stolen), voices.limit, priority)
+		stolen = append(stolen, more...)
+	}
+	voices.Unlock()
+
+	if !ok {
+		return ErrVoiceLimit
+	}
+	for _, v := range stolen {
+		v.states.interrupt(v.howl, v.id)
+		v.states.setPending(v.id, false)
+		v.howl.Call("stop", v.id)
+	}
+	return nil
+}
+
+// started records that sound id has started playing, if it hasn't been
+// already.
+func (s *soundStates) started(id int) {
+	if s == nil {
+		return
+	}
+	voices.Lock()
+	defer voices.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.starts[id]; !ok {
+		voices.seq++
+		s.starts[id] = voices.seq
+	}
+}
+
+// playingVoices returns the sounds of howls that are playing or waiting to,
+// leaving out those in except. The caller must hold voices.
+func playingVoices(howls map[*soundStates]Value, except []voice) []voice {
+	skip := make(map[soundKey]bool, len(except))
+	for _, v := range except {
+		skip[soundKey{v.states, v.id}] = true
+	}
+
+	var playing []voice
+	for s, howl := range howls {
+		for _, id := range soundIDs(howl) {
+			if skip[soundKey{s, id}] {
+				continue
+			}
+			s.mu.Lock()
+			pending := s.pending[id]
+			s.mu.Unlock()
+			if !pending && !howl.Call("playing", id).Bool() {
+				continue
+			}
+			s.mu.Lock()
+			playing = append(playing, voice{
+				states:   s,
+				howl:     howl,
+				id:       id,
+				priority: s.priority,
+				started:  s.starts[id],
+			})
+			s.mu.Unlock()
+		}
+	}
+	return playing
+}
+
+// steal returns the voices to stop so that one more sound can play within
+// limit, or false if the sound must be rejected instead.
+func steal(playing []voice, limit VoiceLimit, priority int) ([]voice, bool) {
+	excess := len(playing) - limit.Max + 1
+	if excess <= 0 {
+		return nil, true
+	}
+
+	var less func(a, b voice) bool
+	switch limit.Steal {
+	case StealOldest:
+		less = func(a, b voice) bool { return false }
+	case StealQuietest:
+		for i, v := range playing {
+			playing[i].volume = v.howl.Call("volume", v.id).Float()
+		}
+		less = func(a, b voice) bool { return a.volume < b.volume }
+	case StealLowestPriority:
+		candidates := playing[:0:0]
+		for _, v := range playing {
+			if v.priority <= priority {
+				candidates = append(candidates, v)
+			}
+		}
+		playing = candidates
+		less = func(a, b voice) bool { return a.priority < b.priority }
+	case StealFarthest:
+		listener := PosVec()
+		for i, v := range playing {
+			pos := vec3Of(v.howl.Call("pos", nil, nil, nil, v.id), 0)
+			dx, dy, dz := pos.X-listener.X, pos.Y-listener.Y, pos.Z-listener.Z
+			playing[i].distance = dx*dx + dy*dy + dz*dz
+		}
+		less = func(a, b voice) bool { return a.distance > b.distance }
+	default:
+		return nil, false
+	}
+	if len(playing) < excess {
+		return nil, false
+	}
+
+	sort.SliceStable(playing, func(i, j int) bool {
+		a, b := playing[i], playing[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if a.started != b.started {
+			return a.started < b.started
+		}
+		return a.id < b.id
+	})
+	return playing[:excess], true
+}
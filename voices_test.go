@@ -0,0 +1,185 @@
+package howler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStealPolicies(t *testing.T) {
+	tests := []struct {
+		name  string
+		steal StealPolicy
+		// priority is the priority of the new sound.
+		priority int
+		// stolen is the playing sound expected to be stopped, or -1 if the
+		// new sound should be rejected.
+		stolen int
+	}{
+		{name: "none", steal: StealNone, stolen: -1},
+		{name: "oldest", steal: StealOldest, stolen: 0},
+		{name: "quietest", steal: StealQuietest, stolen: 1},
+		{name: "lowest priority", steal: StealLowestPriority, priority: 5, stolen: 2},
+		{name: "all higher priority", steal: StealLowestPriority, priority: -1, stolen: -1},
+		{name: "farthest", steal: StealFarthest, stolen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.SetDuration("a.mp3", time.Minute)
+
+			// Three sounds: the oldest is farthest away, the second quietest
+			// and the third has the lowest priority.
+			var playing []Sound
+			for i, p := range []int{3, 2, 1} {
+				h := New(HowlOptions{Source: []string{"a.mp3"}})
+				h.SetPriority(p)
+				s := h.Play()
+				s.SetVolume([]float64{1, 0.2, 0.8}[i])
+				s.SetPos(float64(10-i*4), 0, 0)
+				playing = append(playing, s)
+				b.Advance(time.Millisecond)
+			}
+			SetVoiceLimit(VoiceLimit{Max: 3, Steal: tt.steal})
+
+			h := New(HowlOptions{Source: []string{"a.mp3"}})
+			h.SetPriority(tt.priority)
+			_, err := h.TryPlay()
+			b.Flush()
+
+			if tt.stolen < 0 {
+				if !errors.Is(err, ErrVoiceLimit) {
+					t.Errorf("TryPlay() = %v, want %v", err, ErrVoiceLimit)
+				}
+			} else if err != nil {
+				t.Fatalf("TryPlay() = %v", err)
+			}
+			for i, s := range playing {
+				if want := i != tt.stolen; s.Playing() != want {
+					t.Errorf("sound %d playing = %v, want %v", i, s.Playing(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHowlVoiceLimit(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	h := New(HowlOptions{Source: []string{"a.mp3"}})
+	other := New(HowlOptions{Source: []string{"a.mp3"}})
+	h.SetVoiceLimit(VoiceLimit{Max: 2, Steal: StealOldest})
+
+	first := h.Play()
+	b.Advance(time.Millisecond)
+	second := h.Play()
+	b.Advance(time.Millisecond)
+	// Sounds of other Howls don't count against h's limit.
+	unrelated := other.Play()
+	third, err := h.TryPlay()
+	b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Playing() || !second.Playing() || !third.Playing() || !unrelated.Playing() {
+		t.Errorf("playing = %v, %v, %v, %v; want false, true, true, true",
+			first.Playing(), second.Playing(), third.Playing(), unrelated.Playing())
+	}
+
+	// Playing a sound that is already playing doesn't need a voice.
+	h.SetVoiceLimit(VoiceLimit{Max: 2})
+	if s := second.Play(); !s.Valid() {
+		t.Errorf("replaying a playing sound failed: %v", s.Err())
+	}
+	if _, err := h.TryPlay(); !errors.Is(err, ErrVoiceLimit) {
+		t.Errorf("TryPlay() past the limit = %v, want %v", err, ErrVoiceLimit)
+	}
+}
+
+func TestVoiceLimitBeforeLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		steal StealPolicy
+		// played is how many of the plays should succeed, and playing how
+		// many sounds should play once the Howl has loaded.
+		played, playing int
+	}{
+		{"none", StealNone, 2, 2},
+		{"oldest", StealOldest, 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBackend(t)
+			b.SetDuration("a.mp3", time.Minute)
+			b.SetLoadDelay(time.Second)
+			h := New(HowlOptions{
+				Source:  []string{"a.mp3"},
+				Sprites: map[string]Sprite{"hit": {Duration: time.Second}},
+				Voices:  VoiceLimit{Max: 2, Steal: tt.steal},
+			})
+
+			// Each sprite played while the Howl loads is a sound of its own,
+			// held back until the Howl has loaded.
+			var played int
+			for range 4 {
+				if _, err := h.PlaySprite("hit"); err == nil {
+					played++
+				} else if !errors.Is(err, ErrVoiceLimit) {
+					t.Fatalf("TryPlay() = %v", err)
+				}
+			}
+			if played != tt.played {
+				t.Errorf("%d plays before loading succeeded, want %d", played, tt.played)
+			}
+			b.Advance(time.Second)
+			if n := playingCount(h); n != tt.playing {
+				t.Errorf("%d sounds playing once loaded, want %d", n, tt.playing)
+			}
+		})
+	}
+}
+
+func TestVoiceLimitConcurrent(t *testing.T) {
+	b := newTestBackend(t)
+	b.SetDuration("a.mp3", time.Minute)
+	SetVoiceLimit(VoiceLimit{Max: 3})
+	howls := make([]Howl, 64)
+	for i := range howls {
+		howls[i] = New(HowlOptions{Source: []string{"a.mp3"}})
+	}
+	b.Flush()
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for _, h := range howls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			h.Play()
+		}()
+	}
+	close(start)
+	wg.Wait()
+	b.Flush()
+
+	var playing int
+	for _, h := range howls {
+		playing += playingCount(h)
+	}
+	if playing != 3 {
+		t.Errorf("%d sounds playing, want 3", playing)
+	}
+}
+
+// playingCount returns how many of h's sounds are playing.
+func playingCount(h Howl) int {
+	var n int
+	for _, s := range h.Sounds() {
+		if s.Playing() {
+			n++
+		}
+	}
+	return n
+}